@@ -8,7 +8,7 @@ import (
     "github.com/google/uuid"
 )
 
-func (m *model) duplicateCurrentRow() error {
+func (m *model) duplicateCurrentRow() (err error) {
     if m.db == nil || m.cursor < 0 || m.cursor >= len(m.tables) {
         return fmt.Errorf("no table selected")
     }
@@ -16,6 +16,9 @@ func (m *model) duplicateCurrentRow() error {
         return fmt.Errorf("no row selected")
     }
     table := m.tables[m.cursor]
+    defer func() {
+        if err == nil { m.recordInsert(table) }
+    }()
     // Determine PK
     var pkCols []colInfo
     for _, c := range m.tableCols {
@@ -27,8 +30,7 @@ func (m *model) duplicateCurrentRow() error {
         return fmt.Errorf("composite primary keys not supported for duplicate insert")
     }
     // Build column list
-    colNames := make([]string, 0, len(m.previewColumns))
-    for _, c := range m.previewColumns { colNames = append(colNames, c) }
+    colNames := m.tableColumns()
 
     // Quick helpers
     colType := make(map[string]string, len(m.tableCols))
@@ -40,7 +42,7 @@ func (m *model) duplicateCurrentRow() error {
     }
 
     // Unique indexes
-    uidx, err := getUniqueIndexes(m.db, table)
+    uidx, err := getUniqueIndexes(m.conn(), table)
     if err != nil {
         return err
     }
@@ -73,8 +75,7 @@ func (m *model) duplicateCurrentRow() error {
             where := fmt.Sprintf("%s = ?", quoteIdent(pkName))
             q := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE %s", quoteIdent(table), colsCSV, strings.Join(selectExprs, ", "), quoteIdent(table), where)
             params = append(params, getVal(pkName))
-            _, err := m.db.Exec(q, params...)
-            return err
+            return m.exec(q, params...)
         }
         // Non-integer PK: compute a new value and override
         var newPK any
@@ -83,7 +84,7 @@ func (m *model) duplicateCurrentRow() error {
         } else if isNumericType(pkTypeUpper) {
             var nextVal sql.NullInt64
             q := fmt.Sprintf("SELECT COALESCE(MAX(%s)+1,1) FROM %s", quoteIdent(pkName), quoteIdent(table))
-            if err := m.db.QueryRow(q).Scan(&nextVal); err != nil { return err }
+            if err := m.conn().QueryRow(q).Scan(&nextVal); err != nil { return err }
             if !nextVal.Valid { nextVal.Int64 = 1 }
             newPK = nextVal.Int64
         } else {
@@ -117,23 +118,25 @@ func (m *model) duplicateCurrentRow() error {
     colsCSV := quoteIdentList(targetCols)
     q := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE %s", quoteIdent(table), colsCSV, strings.Join(selectExprs, ", "), quoteIdent(table), whereClause)
     params = append(params, whereParam)
-    _, err = m.db.Exec(q, params...)
-    return err
+    return m.exec(q, params...)
 }
 
 // insertEmptyRow creates a new row using DEFAULT VALUES for the current table.
 // This works when the table has defaults or nullable columns. If NOT NULL
 // constraints without defaults exist, SQLite will return an error which we
 // surface to the user.
-func (m *model) insertEmptyRow() error {
+func (m *model) insertEmptyRow() (err error) {
     if m.db == nil || m.cursor < 0 || m.cursor >= len(m.tables) {
         return fmt.Errorf("no table selected")
     }
     table := m.tables[m.cursor]
+    defer func() {
+        if err == nil { m.recordInsert(table) }
+    }()
     // Prefer DEFAULT VALUES when possible; but if table has NOT NULL columns without defaults,
     // fallback to constructing an explicit INSERT with minimal placeholder values.
     // First, try DEFAULT VALUES quickly.
-    if _, err := m.db.Exec(fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", quoteIdent(table))); err == nil {
+    if err := m.exec(fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", quoteIdent(table))); err == nil {
         return nil
     }
     // Build column/value lists honoring NOT NULL and defaults
@@ -169,15 +172,13 @@ func (m *model) insertEmptyRow() error {
     }
     if len(insertCols) == 0 {
         // Nothing to set explicitly, last resort retry DEFAULT VALUES to surface the original error
-        _, err := m.db.Exec(fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", quoteIdent(table)))
-        return err
+        return m.exec(fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", quoteIdent(table)))
     }
     q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdent(table), quoteIdentList(insertCols), strings.Join(values, ", "))
-    _, err := m.db.Exec(q, params...)
-    return err
+    return m.exec(q, params...)
 }
 
-func (m *model) deleteCurrentRow() error {
+func (m *model) deleteCurrentRow() (err error) {
     if m.db == nil || m.cursor < 0 || m.cursor >= len(m.tables) {
         return fmt.Errorf("no table selected")
     }
@@ -185,6 +186,26 @@ func (m *model) deleteCurrentRow() error {
         return fmt.Errorf("no row selected")
     }
     table := m.tables[m.cursor]
+    // snapshot the row first so the delete can be undone
+    if loc, ok := m.rowLocator(m.selRow); ok {
+        var trashID int64
+        if snap, serr := snapshotRow(m.conn(), table, loc); serr == nil {
+            defer func() {
+                if err == nil { m.pushUndo(undoEntry{kind: undoDelete, table: table, loc: loc, row: snap, trash: trashID}) }
+            }()
+        }
+        // soft delete: keep a copy in the trash table, dropped again if the delete fails
+        if m.trashEnabled && !isTrashTable(table) {
+            id, terr := m.copyToTrash(table, loc)
+            if terr != nil {
+                return fmt.Errorf("copy to trash: %w", terr)
+            }
+            trashID = id
+            defer func() {
+                if err != nil { _ = m.dropFromTrash(table, trashID) }
+            }()
+        }
+    }
     // Prefer explicit PKs
     var pkCols []colInfo
     for _, c := range m.tableCols {
@@ -205,8 +226,7 @@ func (m *model) deleteCurrentRow() error {
             }
         }
         q := fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdent(table), strings.Join(whereParts, " AND "))
-        _, err := m.db.Exec(q, params...)
-        return err
+        return m.exec(q, params...)
     }
     // Fallback to rowid
     if m.previewRowIDs == nil || m.selRow >= len(m.previewRowIDs) {
@@ -214,12 +234,37 @@ func (m *model) deleteCurrentRow() error {
     }
     rowid := m.previewRowIDs[m.selRow]
     q := fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", quoteIdent(table))
-    _, err := m.db.Exec(q, rowid)
-    return err
+    return m.exec(q, rowid)
 }
 
-// commitCellEdit updates the database with the current editBuffer for the selected cell.
-func (m *model) commitCellEdit() error {
+// commitCellEdit updates the database with the cell editor's text for the selected cell.
+func (m *model) commitCellEdit() (err error) {
+    if m.selRow < 0 || m.selRow >= len(m.preview) || m.selCol < 0 || m.selCol >= len(m.previewColumns) {
+        return fmt.Errorf("no cell selected")
+    }
+    colName := m.previewColumns[m.selCol]
+    text := m.cellEditor.value()
+    var newVal any = text
+    if info, ok := m.fieldInfo(colName); ok {
+        // the declared type decides how the text is parsed and validated
+        if newVal, err = parseCellValue(info, text); err != nil {
+            return err
+        }
+    } else if strings.EqualFold(strings.TrimSpace(text), "NULL") {
+        // Interpret literal NULL (case-insensitive) as SQL NULL
+        newVal = nil
+    }
+    if s, ok := newVal.(string); ok && m.wantsJSON(colName, m.preview[m.selRow][m.selCol]) {
+        if err := m.checkJSON(colName, s); err != nil {
+            return err
+        }
+    }
+    return m.updateSelectedCell(newVal)
+}
+
+// updateSelectedCell writes newVal (bound as-is, so []byte stays a BLOB) to
+// the selected cell, keyed by primary key or rowid.
+func (m *model) updateSelectedCell(newVal any) (err error) {
     if m.db == nil || m.cursor < 0 || m.cursor >= len(m.tables) {
         return fmt.Errorf("no table selected")
     }
@@ -231,11 +276,25 @@ func (m *model) commitCellEdit() error {
     }
     table := m.tables[m.cursor]
     colName := m.previewColumns[m.selCol]
-    // Interpret literal NULL (case-insensitive) as SQL NULL
-    var newVal any = m.editBuffer
-    if strings.EqualFold(strings.TrimSpace(m.editBuffer), "NULL") {
-        newVal = nil
+    // highlight the cell while its change is staged, and remember the old
+    // value so the edit can be undone
+    row := m.selRow
+    loc, hasLoc := m.rowLocator(row)
+    var before any
+    if hasLoc {
+        var rerr error
+        if before, rerr = readCell(m.conn(), table, loc, colName); rerr != nil {
+            hasLoc = false // edit still goes ahead, it just can't be undone
+        }
     }
+    defer func() {
+        if err != nil { return }
+        m.markCellChanged(row, colName)
+        if hasLoc {
+            after, rerr := readCell(m.conn(), table, loc.with(colName, newVal), colName)
+            if rerr == nil { m.recordUpdate(table, loc, colName, before, after) }
+        }
+    }()
     // Prefer explicit PKs for WHERE condition; support composite PK
     var pkCols []colInfo
     for _, c := range m.tableCols {
@@ -258,8 +317,7 @@ func (m *model) commitCellEdit() error {
             }
         }
         q := fmt.Sprintf("UPDATE %s SET %s WHERE %s", quoteIdent(table), setExpr, strings.Join(whereParts, " AND "))
-        _, err := m.db.Exec(q, params...)
-        return err
+        return m.exec(q, params...)
     }
     // Fallback to rowid
     if m.previewRowIDs == nil || m.selRow >= len(m.previewRowIDs) {
@@ -268,7 +326,17 @@ func (m *model) commitCellEdit() error {
     rowid := m.previewRowIDs[m.selRow]
     setExpr := fmt.Sprintf("%s = ?", quoteIdent(colName))
     q := fmt.Sprintf("UPDATE %s SET %s WHERE rowid = ?", quoteIdent(table), setExpr)
-    _, err := m.db.Exec(q, newVal, rowid)
+    return m.exec(q, newVal, rowid)
+}
+
+// exec runs a mutating statement and counts it toward staged changes. The
+// statement's last insert rowid is kept for recording inserts.
+func (m *model) exec(q string, args ...any) error {
+    res, err := m.conn().Exec(q, args...)
+    if err == nil {
+        m.noteChange()
+        m.lastInsertID, _ = res.LastInsertId()
+    }
     return err
 }
 
@@ -316,7 +384,7 @@ func (m *model) computeUniqueOverrides(table string, insertCols []string, colTyp
         } else if isNumericType(colType[lc]) {
             var nextVal sql.NullInt64
             q := fmt.Sprintf("SELECT COALESCE(MAX(%s)+1,1) FROM %s", quoteIdent(choose), quoteIdent(table))
-            if err := m.db.QueryRow(q).Scan(&nextVal); err != nil { return err }
+            if err := m.conn().QueryRow(q).Scan(&nextVal); err != nil { return err }
             if !nextVal.Valid { nextVal.Int64 = 1 }
             overrides[lc] = nextVal.Int64
         } else {
@@ -336,7 +404,7 @@ func (m *model) deleteCurrentTable() error {
     typ := m.confirmDeleteType
     if typ == "" {
         var err error
-        typ, err = getObjectType(m.db, name)
+        typ, err = getObjectType(m.conn(), name)
         if err != nil { return err }
     }
     stmt := ""
@@ -345,6 +413,5 @@ func (m *model) deleteCurrentTable() error {
     } else {
         stmt = fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(name))
     }
-    _, err := m.db.Exec(stmt)
-    return err
+    return m.exec(stmt)
 }
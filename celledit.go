@@ -0,0 +1,48 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+)
+
+// editOverlayMin is the value length (in runes) from which the cell editor
+// opens as a full-pane overlay instead of editing inside the grid cell.
+const editOverlayMin = 40
+
+// editOverlay reports whether the current edit is shown in the overlay:
+// multi-line or long values don't fit in a grid cell.
+func (m model) editOverlay() bool {
+    if !m.editingActive {
+        return false
+    }
+    return len(m.cellEditor.buf) > editOverlayMin || strings.ContainsRune(m.cellEditor.value(), '\n')
+}
+
+// activeCellEditor returns the editor the grid should draw in the selected
+// cell, or nil when no inline edit is in progress.
+func (m model) activeCellEditor() *textArea {
+    if !m.editingActive || m.editOverlay() {
+        return nil
+    }
+    return &m.cellEditor
+}
+
+// renderCellEditor draws the overlay editor for the selected cell into b.
+func (m model) renderCellEditor(b *strings.Builder, width int) {
+    col := m.previewColumns[m.selCol]
+    title := fmt.Sprintf("Edit: %s.%s", m.previewTable, col)
+    if c, ok := m.fieldInfo(col); ok {
+        title += " " + styleDim.Render(fieldMeta(c))
+    }
+    line, pos := m.cellEditor.lineCol()
+    title += " " + stylePrompt.Render("EDITING") + styleDim.Render(fmt.Sprintf(" ln %d, col %d", line+1, pos+1))
+    b.WriteString(styleHeader.Render(title) + "\n")
+    b.WriteString(styleDim.Render("Enter/Ctrl+S save, Alt+Enter newline, Ctrl/Alt+←/→ word, Ctrl+W delete word, Esc cancel") + "\n")
+    if m.editErr != "" {
+        b.WriteString("  " + styleError.Render("invalid value: "+m.editErr) + "\n")
+    }
+    h := max(3, m.height-5)
+    for _, l := range m.cellEditor.render(max(1, width-4), h, true) {
+        b.WriteString("    " + l + "\n")
+    }
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+    "bufio"
+    "database/sql"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+
+// cliCommand is a headless subcommand for scripts: it runs against the
+// database and writes its result to stdout instead of starting the TUI.
+type cliCommand struct {
+    name  string
+    usage string
+    help  string
+    run   func(db *sql.DB, args []string, w io.Writer) error
+}
+
+var cliCommands = []cliCommand{
+    {"tables", "tables", "list tables and views, one per line", cliTables},
+    {"schema", "schema [--format f] [--ddl] <table>", "show the columns of a table (--ddl: CREATE statements)", cliSchema},
+    {"query", "query [--format f] \"<sql>\"", "run a statement and print its rows (default format: table)", cliQuery},
+    {"export", "export [--format f] <table>", "write every row of a table (default format: csv)", cliExport},
+}
+
+func findCLICommand(name string) (cliCommand, bool) {
+    for _, c := range cliCommands {
+        if c.name == name {
+            return c, true
+        }
+    }
+    return cliCommand{}, false
+}
+
+// usageError is a bad invocation; it exits with status 2 rather than 1.
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+// runCLI runs subcommand c and returns the process exit code: 0 on success,
+// 1 when the database or a statement fails, 2 for usage errors.
+func runCLI(c cliCommand, args []string, opts options) int {
+    if resolveDBPath(opts) == "" {
+        fmt.Fprintln(os.Stderr, "No SQLite .db file found. Pass --db <path> or set DB_PATH. Alternatively, place a .db in the current directory or in 'instance/'.")
+        return 2
+    }
+    db, err := openDB(opts)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "%s: %v\n", appName, err)
+        return 1
+    }
+    defer db.Close()
+    w := bufio.NewWriter(os.Stdout)
+    err = c.run(db, args, w)
+    if ferr := w.Flush(); err == nil {
+        err = ferr
+    }
+    var uerr usageError
+    switch {
+    case errors.Is(err, flag.ErrHelp):
+        fmt.Fprintf(os.Stderr, "Usage: %s %s\n  %s\n", appName, c.usage, c.help)
+        return 0
+    case errors.As(err, &uerr):
+        fmt.Fprintf(os.Stderr, "%s %s: %v\nUsage: %s %s\n", appName, c.name, err, appName, c.usage)
+        return 2
+    case err != nil:
+        fmt.Fprintf(os.Stderr, "%s %s: %v\n", appName, c.name, err)
+        return 1
+    }
+    return 0
+}
+
+// parseCLIArgs parses fs from args, allowing flags before or after the
+// positional arguments, and checks that exactly want positionals remain.
+// Anything after "--" is positional.
+func parseCLIArgs(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+    fs.SetOutput(io.Discard)
+    var pos []string
+    for {
+        if err := fs.Parse(args); err != nil {
+            if errors.Is(err, flag.ErrHelp) {
+                return nil, err
+            }
+            return nil, usageError{err.Error()}
+        }
+        rest := fs.Args()
+        if used := len(args) - len(rest); used > 0 && args[used-1] == "--" {
+            pos = append(pos, rest...)
+            break
+        }
+        if len(rest) == 0 {
+            break
+        }
+        pos = append(pos, rest[0])
+        args = rest[1:]
+    }
+    if len(pos) != want {
+        return nil, usageError{fmt.Sprintf("expected %d argument(s), got %d", want, len(pos))}
+    }
+    return pos, nil
+}
+
+// formatFlag adds the --format flag shared by the commands that print rows.
+func formatFlag(fs *flag.FlagSet, def string) *string {
+    return fs.String("format", def, "output format: "+strings.Join(exportFormats, ", "))
+}
+
+func cliTables(db *sql.DB, args []string, w io.Writer) error {
+    if _, err := parseCLIArgs(flag.NewFlagSet("tables", flag.ContinueOnError), args, 0); err != nil {
+        return err
+    }
+    tables, err := listTables(db)
+    if err != nil {
+        return err
+    }
+    for _, t := range tables {
+        if _, err := fmt.Fprintln(w, t); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func cliSchema(db *sql.DB, args []string, w io.Writer) error {
+    fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+    format := formatFlag(fs, "table")
+    ddl := fs.Bool("ddl", false, "print the CREATE statements of the table, its indexes and triggers")
+    pos, err := parseCLIArgs(fs, args, 1)
+    if err != nil {
+        return err
+    }
+    table := pos[0]
+    cols, err := getTableInfo(db, table)
+    if err != nil {
+        return err
+    }
+    if len(cols) == 0 {
+        return fmt.Errorf("no such table: %s", table)
+    }
+    if *ddl {
+        return writeSchemaDDL(db, table, w)
+    }
+    rw, err := newRowWriter(*format, w, table+"_columns")
+    if err != nil {
+        return usageError{err.Error()}
+    }
+    if err := rw.header([]string{"name", "type", "notnull", "default", "pk"}); err != nil {
+        return err
+    }
+    for _, c := range cols {
+        var def any
+        if c.Default.Valid {
+            def = c.Default.String
+        }
+        notNull := int64(0)
+        if c.NotNull {
+            notNull = 1
+        }
+        if err := rw.row([]any{c.Name, c.Type, notNull, def, int64(c.PKOrder)}); err != nil {
+            return err
+        }
+    }
+    return rw.close()
+}
+
+// writeSchemaDDL prints the stored CREATE statements for table and its
+// explicit indexes and triggers, like the sqlite3 shell's .schema.
+func writeSchemaDDL(db dbConn, table string, w io.Writer) error {
+    ddl, err := getCreateSQL(db, table)
+    if err != nil {
+        return err
+    }
+    stmts := []string{ddl}
+    idxs, err := getIndexes(db, table)
+    if err != nil {
+        return err
+    }
+    for _, ix := range idxs {
+        if ix.SQL != "" { stmts = append(stmts, ix.SQL) }
+    }
+    trigs, err := getTriggers(db, table)
+    if err != nil {
+        return err
+    }
+    for _, t := range trigs {
+        stmts = append(stmts, t.SQL)
+    }
+    for _, s := range stmts {
+        if _, err := fmt.Fprintln(w, strings.TrimSpace(s)+";"); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func cliQuery(db *sql.DB, args []string, w io.Writer) error {
+    fs := flag.NewFlagSet("query", flag.ContinueOnError)
+    format := formatFlag(fs, "table")
+    pos, err := parseCLIArgs(fs, args, 1)
+    if err != nil {
+        return err
+    }
+    q := pos[0]
+    if strings.TrimSpace(q) == "" {
+        return usageError{"empty query"}
+    }
+    rw, err := newRowWriter(*format, w, "query_result")
+    if err != nil {
+        return usageError{err.Error()}
+    }
+    if !returnsRows(q) {
+        r, err := db.Exec(q)
+        if err != nil {
+            return err
+        }
+        n, _ := r.RowsAffected()
+        // keep stdout for data; the count goes to stderr
+        fmt.Fprintf(os.Stderr, "%d row(s) affected\n", n)
+        return nil
+    }
+    rows, err := db.Query(q)
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+    _, err = writeRows(rw, rows)
+    return err
+}
+
+func cliExport(db *sql.DB, args []string, w io.Writer) error {
+    fs := flag.NewFlagSet("export", flag.ContinueOnError)
+    format := formatFlag(fs, "csv")
+    pos, err := parseCLIArgs(fs, args, 1)
+    if err != nil {
+        return err
+    }
+    table := pos[0]
+    rw, err := newRowWriter(*format, w, table)
+    if err != nil {
+        return usageError{err.Error()}
+    }
+    cols, err := getTableInfo(db, table)
+    if err != nil {
+        return err
+    }
+    if len(cols) == 0 {
+        return fmt.Errorf("no such table: %s", table)
+    }
+    // read columns raw, as the TUI export does, so dates are written as stored
+    exprs := make([]string, len(cols))
+    for i, c := range cols {
+        exprs[i] = rawExpr(c.Name) + " AS " + quoteIdent(c.Name)
+    }
+    rows, err := db.Query(fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), quoteIdent(table)))
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+    _, err = writeRows(rw, rows)
+    return err
+}
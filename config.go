@@ -0,0 +1,184 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// configValue is one key = value setting from the config file. Strings are
+// unquoted; numbers and booleans keep their literal text.
+type configValue struct {
+    raw  string
+    line int
+}
+
+// config is the parsed config file: settings before the first [section]
+// header, and the settings of each section by its (dotted) name.
+type config struct {
+    path         string
+    values       map[string]configValue
+    sections     map[string]map[string]configValue
+    order        []string // section names in file order
+    sectionLines map[string]int
+}
+
+func newConfig(path string) *config {
+    return &config{path: path, values: map[string]configValue{}, sections: map[string]map[string]configValue{}, sectionLines: map[string]int{}}
+}
+
+// errorf reports a problem on line of the config file.
+func (c *config) errorf(line int, format string, args ...any) error {
+    return fmt.Errorf("%s:%d: %s", c.path, line, fmt.Sprintf(format, args...))
+}
+
+// configPath is the config file: $TUI_SQL_CONFIG, else config.toml in the
+// user config directory (e.g. ~/.config/tui-sql/config.toml).
+func configPath() (string, error) {
+    if p := os.Getenv("TUI_SQL_CONFIG"); p != "" {
+        return p, nil
+    }
+    dir, err := configDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "config.toml"), nil
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig() (*config, error) {
+    path, err := configPath()
+    if err != nil {
+        return newConfig(""), nil
+    }
+    f, err := os.Open(path)
+    if errors.Is(err, fs.ErrNotExist) {
+        return newConfig(path), nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return parseConfig(path, f)
+}
+
+var (
+    configSectionRe = regexp.MustCompile(`^\[\s*([A-Za-z0-9_-]+(?:\s*\.\s*[A-Za-z0-9_-]+)*)\s*\]$`)
+    configKeyRe     = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+    configNumberRe  = regexp.MustCompile(`^[+-]?[0-9][0-9_]*(\.[0-9_]+)?$`)
+)
+
+// parseConfig reads the subset of TOML the config uses: [section] headers,
+// key = value lines with "basic" or 'literal' strings, integers, decimals and
+// booleans, and # comments.
+func parseConfig(path string, r io.Reader) (*config, error) {
+    c := newConfig(path)
+    cur := c.values
+    sc := bufio.NewScanner(r)
+    for n := 1; sc.Scan(); n++ {
+        line := strings.TrimSpace(sc.Text())
+        if n == 1 { line = strings.TrimPrefix(line, "\ufeff") }
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        if strings.HasPrefix(line, "[") {
+            line = strings.TrimSpace(stripConfigComment(line))
+            sm := configSectionRe.FindStringSubmatch(line)
+            if sm == nil {
+                return nil, c.errorf(n, "bad section header %s", line)
+            }
+            name := strings.ReplaceAll(sm[1], " ", "")
+            if _, dup := c.sections[name]; dup {
+                return nil, c.errorf(n, "section [%s] appears twice", name)
+            }
+            cur = map[string]configValue{}
+            c.sections[name] = cur
+            c.order = append(c.order, name)
+            c.sectionLines[name] = n
+            continue
+        }
+        key, rest, ok := strings.Cut(line, "=")
+        key = strings.TrimSpace(key)
+        if !ok || !configKeyRe.MatchString(key) {
+            return nil, c.errorf(n, "expected key = value, got %s", line)
+        }
+        if _, dup := cur[key]; dup {
+            return nil, c.errorf(n, "%s is set twice", key)
+        }
+        val, err := parseConfigValue(strings.TrimSpace(rest))
+        if err != nil {
+            return nil, c.errorf(n, "%s: %v", key, err)
+        }
+        cur[key] = configValue{raw: val, line: n}
+    }
+    if err := sc.Err(); err != nil {
+        return nil, err
+    }
+    return c, nil
+}
+
+// parseConfigValue decodes the value part of a key = value line.
+func parseConfigValue(s string) (string, error) {
+    switch {
+    case strings.HasPrefix(s, `"`):
+        end := closingQuote(s)
+        if end < 0 {
+            return "", fmt.Errorf("unterminated string")
+        }
+        if rest := strings.TrimSpace(s[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+            return "", fmt.Errorf("unexpected %s after string", rest)
+        }
+        v, err := strconv.Unquote(s[:end+1])
+        if err != nil {
+            return "", fmt.Errorf("bad string %s", s[:end+1])
+        }
+        return v, nil
+    case strings.HasPrefix(s, "'"):
+        end := strings.Index(s[1:], "'")
+        if end < 0 {
+            return "", fmt.Errorf("unterminated string")
+        }
+        if rest := strings.TrimSpace(s[end+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+            return "", fmt.Errorf("unexpected %s after string", rest)
+        }
+        return s[1 : end+1], nil
+    }
+    v := strings.TrimSpace(stripConfigComment(s))
+    switch {
+    case v == "":
+        return "", fmt.Errorf("missing value")
+    case v == "true" || v == "false":
+        return v, nil
+    case configNumberRe.MatchString(v):
+        return strings.ReplaceAll(v, "_", ""), nil
+    }
+    return "", fmt.Errorf("%s is not a string, number or boolean (quote strings)", v)
+}
+
+// closingQuote returns the index of the quote ending the basic string at the
+// start of s, or -1.
+func closingQuote(s string) int {
+    for i := 1; i < len(s); i++ {
+        switch s[i] {
+        case '\\':
+            i++
+        case '"':
+            return i
+        }
+    }
+    return -1
+}
+
+func stripConfigComment(s string) string {
+    if i := strings.Index(s, "#"); i >= 0 {
+        return s[:i]
+    }
+    return s
+}
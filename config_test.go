@@ -0,0 +1,63 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestParseConfigValue(t *testing.T) {
+    tests := []struct {
+        in      string
+        want    string
+        wantErr bool
+    }{
+        {`"a \"b\" # c"  # comment`, `a "b" # c`, false},
+        {`'C:\path'`, `C:\path`, false},
+        {"1_000", "1000", false},
+        {"-2.5 # note", "-2.5", false},
+        {"true", "true", false},
+        {`"open`, "", true},
+        {`"a" b`, "", true},
+        {"bare", "", true},
+        {"", "", true},
+    }
+    for _, tt := range tests {
+        got, err := parseConfigValue(tt.in)
+        if (err != nil) != tt.wantErr || got != tt.want {
+            t.Errorf("parseConfigValue(%q) = %q, %v, want %q (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+        }
+    }
+}
+
+func TestParseConfig(t *testing.T) {
+    in := "\ufeff# settings\ntheme = \"dark\"\n\n[ keys ]\nquit = \"Q\" # capital\n[themes . mine]\nheader = '#ff0000'\n"
+    c, err := parseConfig("cfg", strings.NewReader(in))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if v := c.values["theme"]; v.raw != "dark" || v.line != 2 {
+        t.Errorf("theme = %+v", v)
+    }
+    if v := c.sections["keys"]["quit"]; v.raw != "Q" || v.line != 5 {
+        t.Errorf("keys.quit = %+v", v)
+    }
+    if v := c.sections["themes.mine"]["header"]; v.raw != "#ff0000" {
+        t.Errorf("themes.mine.header = %+v", v)
+    }
+
+    errs := []struct {
+        in   string
+        want string
+    }{
+        {"a = 1\na = 2", "cfg:2: a is set twice"},
+        {"[keys]\n[keys]", "cfg:2: section [keys] appears twice"},
+        {"[keys", "cfg:1: bad section header"},
+        {"just words", "cfg:1: expected key = value"},
+        {"x = nope", "cfg:1: x: nope is not a string"},
+    }
+    for _, tt := range errs {
+        if _, err := parseConfig("cfg", strings.NewReader(tt.in)); err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+            t.Errorf("parseConfig(%q) error = %v, want %q", tt.in, err, tt.want)
+        }
+    }
+}
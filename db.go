@@ -8,19 +8,63 @@ import (
     "strings"
 )
 
-func openDB() (*sql.DB, error) {
+// dbConn is what the queries in this package need; both *sql.DB and *sql.Tx
+// satisfy it, so staging mode can route everything through one transaction.
+type dbConn interface {
+    Exec(query string, args ...any) (sql.Result, error)
+    Query(query string, args ...any) (*sql.Rows, error)
+    QueryRow(query string, args ...any) *sql.Row
+}
+
+func openDB(opts options) (*sql.DB, error) {
     // Use modernc.org/sqlite (pure Go) so user doesn't need CGO
-    path := resolveDBPath()
+    path := resolveDBPath(opts)
     if path == "" {
         return nil, fmt.Errorf("no SQLite .db file found. Provide a path: 'go run . <db path>' or set DB_PATH, or place a .db in current directory or in 'instance/'")
     }
-    return sql.Open("sqlite", path)
+    db, err := sql.Open("sqlite", sqliteDSN(path, opts))
+    if err != nil {
+        return nil, err
+    }
+    // connect now so a bad PRAGMA (e.g. WAL on a read-only file) fails here
+    if err := db.Ping(); err != nil {
+        db.Close()
+        return nil, fmt.Errorf("open %s: %w", path, err)
+    }
+    return db, nil
+}
+
+// sqliteDSN builds the driver DSN for path. The connection PRAGMAs go in the
+// URI so every pooled connection gets them. In read-only mode the file is
+// opened with mode=ro and the connection is also put into query_only, so
+// writes fail in SQLite itself and not just behind the UI checks.
+func sqliteDSN(path string, opts options) string {
+    var params []string
+    if opts.readOnly {
+        params = append(params, "mode=ro", "_pragma=query_only(1)")
+    }
+    if opts.busyTimeout > 0 {
+        params = append(params, fmt.Sprintf("_pragma=busy_timeout(%d)", opts.busyTimeout.Milliseconds()))
+    }
+    // changing the journal mode is a write, so a read-only open keeps the file's
+    if opts.journalMode != "" && !opts.readOnly {
+        params = append(params, "_pragma=journal_mode("+opts.journalMode+")")
+    }
+    if opts.foreignKeys {
+        params = append(params, "_pragma=foreign_keys(1)")
+    }
+    if len(params) == 0 {
+        return path
+    }
+    // escape the characters that would end the path part of a file: URI
+    p := strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(path)
+    return "file:" + p + "?" + strings.Join(params, "&")
 }
 
-func resolveDBPath() string {
-    // 1) CLI arg: go run . <db path>
-    if len(os.Args) > 1 && os.Args[1] != "" {
-        return os.Args[1]
+func resolveDBPath(opts options) string {
+    // 1) CLI arg: go run . <db path>, or --db
+    if opts.dbPath != "" {
+        return opts.dbPath
     }
     // 2) Env override
     if p := os.Getenv("DB_PATH"); p != "" {
@@ -69,7 +113,7 @@ func newestDBInDir(dir string) (string, bool) {
     return newestPath, true
 }
 
-func listTables(db *sql.DB) ([]string, error) {
+func listTables(db dbConn) ([]string, error) {
     rows, err := db.Query(`SELECT name FROM sqlite_schema WHERE type IN ('table','view') AND name NOT LIKE 'sqlite_%' ORDER BY name`)
     if err != nil {
         return nil, err
@@ -87,7 +131,7 @@ func listTables(db *sql.DB) ([]string, error) {
 }
 
 // getTableInfo returns column info for the given table
-func getTableInfo(db *sql.DB, table string) ([]colInfo, error) {
+func getTableInfo(db dbConn, table string) ([]colInfo, error) {
     q := fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(table))
     rows, err := db.Query(q)
     if err != nil {
@@ -110,13 +154,30 @@ func getTableInfo(db *sql.DB, table string) ([]colInfo, error) {
     return out, rows.Err()
 }
 
-func getUniqueIndexes(db *sql.DB, table string) ([]uniqueIndex, error) {
+func getUniqueIndexes(db dbConn, table string) ([]uniqueIndex, error) {
+    idxs, err := getIndexes(db, table)
+    if err != nil { return nil, err }
+    out := make([]uniqueIndex, 0, len(idxs))
+    for _, ix := range idxs {
+        // skip non-unique and the implicit PK unique index if any
+        if !ix.Unique || strings.EqualFold(ix.Origin, "pk") { continue }
+        var cols []string
+        for _, c := range ix.Columns {
+            if c != "" { cols = append(cols, c) }
+        }
+        if len(cols) > 0 { out = append(out, uniqueIndex{Name: ix.Name, Columns: cols}) }
+    }
+    return out, nil
+}
+
+// getIndexes returns every index on table with its columns. Expression
+// columns have an empty name.
+func getIndexes(db dbConn, table string) ([]indexInfo, error) {
     q := fmt.Sprintf("PRAGMA index_list(%s)", quoteIdent(table))
     rows, err := db.Query(q)
     if err != nil { return nil, err }
     defer rows.Close()
-    type idxRow struct{ name string; unique int; origin string }
-    var idxs []idxRow
+    var idxs []indexInfo
     for rows.Next() {
         var seq int
         var name string
@@ -124,31 +185,105 @@ func getUniqueIndexes(db *sql.DB, table string) ([]uniqueIndex, error) {
         var origin string
         var partial int
         if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil { return nil, err }
-        if unique == 1 { idxs = append(idxs, idxRow{name: name, unique: unique, origin: origin}) }
+        idxs = append(idxs, indexInfo{Name: name, Unique: unique == 1, Origin: origin, Partial: partial == 1})
     }
     if err := rows.Err(); err != nil { return nil, err }
-    // For each index, get cols
-    out := make([]uniqueIndex, 0, len(idxs))
-    for _, ix := range idxs {
-        // skip implicit PK unique index if any
-        if strings.EqualFold(ix.origin, "pk") { continue }
-        qi := fmt.Sprintf("PRAGMA index_info(%s)", quoteIdent(ix.name))
+    rows.Close()
+    // For each index, get cols and its CREATE statement (NULL for automatic indexes)
+    for i := range idxs {
+        qi := fmt.Sprintf("PRAGMA index_info(%s)", quoteIdent(idxs[i].Name))
         r2, err := db.Query(qi)
         if err != nil { return nil, err }
-        var cols []string
         for r2.Next() {
             var seqno, cid int
-            var cname string
+            var cname sql.NullString
             if err := r2.Scan(&seqno, &cid, &cname); err != nil { r2.Close(); return nil, err }
-            if cname != "" { cols = append(cols, cname) }
+            idxs[i].Columns = append(idxs[i].Columns, cname.String)
         }
         r2.Close()
-        if len(cols) > 0 { out = append(out, uniqueIndex{Name: ix.name, Columns: cols}) }
+        var ddl sql.NullString
+        err = db.QueryRow(`SELECT sql FROM sqlite_schema WHERE type = 'index' AND name = ?`, idxs[i].Name).Scan(&ddl)
+        if err != nil && err != sql.ErrNoRows { return nil, err }
+        idxs[i].SQL = ddl.String
+    }
+    return idxs, nil
+}
+
+// getCreateSQL returns the original CREATE statement stored in sqlite_schema.
+func getCreateSQL(db dbConn, name string) (string, error) {
+    var ddl sql.NullString
+    err := db.QueryRow(`SELECT sql FROM sqlite_schema WHERE name = ? AND type IN ('table','view')`, name).Scan(&ddl)
+    return ddl.String, err
+}
+
+// getTriggers returns the triggers defined on table.
+func getTriggers(db dbConn, table string) ([]triggerInfo, error) {
+    rows, err := db.Query(`SELECT name, COALESCE(sql, '') FROM sqlite_schema WHERE type = 'trigger' AND tbl_name = ? ORDER BY name`, table)
+    if err != nil { return nil, err }
+    defer rows.Close()
+    var out []triggerInfo
+    for rows.Next() {
+        var t triggerInfo
+        if err := rows.Scan(&t.Name, &t.SQL); err != nil { return nil, err }
+        out = append(out, t)
+    }
+    return out, rows.Err()
+}
+
+// getForeignKeys returns the outgoing foreign keys of table, one entry per
+// constraint with its column pairs in order.
+func getForeignKeys(db dbConn, table string) ([]foreignKey, error) {
+    q := fmt.Sprintf("PRAGMA foreign_key_list(%s)", quoteIdent(table))
+    rows, err := db.Query(q)
+    if err != nil { return nil, err }
+    defer rows.Close()
+    var out []foreignKey
+    byID := make(map[int]int)
+    for rows.Next() {
+        // id, seq, table, from, to, on_update, on_delete, match
+        var id, seq int
+        var parent, from string
+        var to sql.NullString
+        var onUpdate, onDelete, match string
+        if err := rows.Scan(&id, &seq, &parent, &from, &to, &onUpdate, &onDelete, &match); err != nil { return nil, err }
+        i, ok := byID[id]
+        if !ok {
+            i = len(out)
+            byID[id] = i
+            out = append(out, foreignKey{ID: id, Table: table, RefTable: parent, OnUpdate: onUpdate, OnDelete: onDelete})
+        }
+        out[i].From = append(out[i].From, from)
+        // a NULL "to" column refers to the parent's primary key
+        out[i].To = append(out[i].To, to.String)
+    }
+    return out, rows.Err()
+}
+
+// getIncomingForeignKeys returns the foreign keys in other tables that
+// reference table.
+func getIncomingForeignKeys(db dbConn, table string) ([]foreignKey, error) {
+    rows, err := db.Query(`SELECT name FROM sqlite_schema WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+    if err != nil { return nil, err }
+    var tables []string
+    for rows.Next() {
+        var name string
+        if err := rows.Scan(&name); err != nil { rows.Close(); return nil, err }
+        tables = append(tables, name)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil { return nil, err }
+    var out []foreignKey
+    for _, t := range tables {
+        fks, err := getForeignKeys(db, t)
+        if err != nil { return nil, err }
+        for _, fk := range fks {
+            if strings.EqualFold(fk.RefTable, table) { out = append(out, fk) }
+        }
     }
     return out, nil
 }
 
-func getObjectType(db *sql.DB, name string) (string, error) {
+func getObjectType(db dbConn, name string) (string, error) {
     var typ string
     err := db.QueryRow(`SELECT type FROM sqlite_schema WHERE name = ? LIMIT 1`, name).Scan(&typ)
     if err != nil { return "", err }
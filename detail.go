@@ -0,0 +1,194 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateDetail handles keys in the full-row detail view. The selected field is
+// the preview's selCol, so edits go through the usual commitCellEdit path.
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    if m.jsonActive {
+        return m.updateJSONView(msg)
+    }
+    switch m.actionFor(msg.String(), "Detail view", "Any view") {
+    case "quit":
+        return m.quit()
+    case "back":
+        m.detailActive = false
+    case "up":
+        if m.selCol > 0 { m.selCol-- }
+        m.scrollDetailToField()
+    case "down":
+        if m.selCol+1 < len(m.previewColumns) { m.selCol++ }
+        m.scrollDetailToField()
+    case "left":
+        // previous record, crossing into the previous page if needed
+        if m.selRow > 0 {
+            m.selRow--
+        } else if m.pageOffset > 0 {
+            m.gotoPage(m.prevPage)
+            m.selRow = max(0, len(m.preview)-1)
+        }
+        m.detailScroll = 0
+    case "right":
+        if m.selRow+1 < len(m.preview) {
+            m.selRow++
+        } else if m.pageOffset+len(m.preview) < m.totalRows {
+            m.gotoPage(m.nextPage)
+            m.selRow = 0
+        }
+        m.detailScroll = 0
+    case "page-down":
+        m.detailScroll += max(1, m.detailHeight()/2)
+        m.detailScroll = min(m.detailScroll, max(0, len(m.detailLines(m.gridWidth()))-1))
+    case "page-up":
+        m.detailScroll = max(0, m.detailScroll-max(1, m.detailHeight()/2))
+    case "edit":
+        m.beginCellEdit()
+    case "edit-external":
+        return m, m.openExternalEditor()
+    case "json":
+        m.openJSONView()
+    case "hex":
+        m.openHexView()
+    case "copy":
+        m.copySelectedCell()
+    }
+    if m.selRow >= len(m.preview) {
+        m.detailActive = false
+    }
+    return m, nil
+}
+
+// detailHeight is the number of body lines that fit under the detail title.
+func (m model) detailHeight() int {
+    if m.height > 4 {
+        return m.height - 4
+    }
+    return 20
+}
+
+// fieldInfo returns the declared column info for a preview column, if known.
+func (m model) fieldInfo(name string) (colInfo, bool) {
+    for _, c := range m.tableCols {
+        if strings.EqualFold(c.Name, name) {
+            return c, true
+        }
+    }
+    return colInfo{}, false
+}
+
+// fieldMeta describes a column's declared type and constraints, e.g.
+// "INTEGER NOT NULL DEFAULT 0 PK".
+func fieldMeta(c colInfo) string {
+    var parts []string
+    if t := strings.TrimSpace(c.Type); t != "" {
+        parts = append(parts, strings.ToUpper(t))
+    } else {
+        parts = append(parts, "(no type)")
+    }
+    if c.NotNull {
+        parts = append(parts, "NOT NULL")
+    }
+    if c.Default.Valid {
+        parts = append(parts, "DEFAULT "+c.Default.String)
+    }
+    if c.PKOrder > 0 {
+        parts = append(parts, "PK")
+    }
+    return strings.Join(parts, " ")
+}
+
+// detailLines lays out the selected record for display.
+func (m model) detailLines(width int) []string {
+    lines, _ := m.detailLayout(width)
+    return lines
+}
+
+// detailLayout renders the selected record one field per block: a name line
+// with its declared type, followed by the wrapped value. It also returns the
+// line index where each field starts.
+func (m model) detailLayout(width int) ([]string, []int) {
+    if m.selRow < 0 || m.selRow >= len(m.preview) {
+        return nil, nil
+    }
+    row := m.preview[m.selRow]
+    var lines []string
+    starts := make([]int, len(m.previewColumns))
+    for i, name := range m.previewColumns {
+        starts[i] = len(lines)
+        cursor := "  "
+        label := name
+        if i == m.selCol {
+            cursor = styleCursor.Render("> ")
+            label = styleColSelect.Render(name)
+        }
+        meta := ""
+        if c, ok := m.fieldInfo(name); ok {
+            meta = " " + styleDim.Render(fieldMeta(c))
+        }
+        lines = append(lines, cursor+label+meta)
+        val := ""
+        if i < len(row) { val = row[i] }
+        if m.editingActive && i == m.selCol {
+            for _, l := range m.cellEditor.render(max(1, width-4), 0, true) {
+                lines = append(lines, "    "+l)
+            }
+            continue
+        }
+        if pretty := jsonPrettyLines(val, max(1, width-4)); pretty != nil {
+            for _, l := range pretty {
+                lines = append(lines, "    "+l)
+            }
+            continue
+        }
+        for _, w := range wrapText(val, max(1, width-4)) {
+            lines = append(lines, "    "+w)
+        }
+    }
+    return lines, starts
+}
+
+// scrollDetailToField keeps the selected field's name line on screen.
+func (m *model) scrollDetailToField() {
+    lines, starts := m.detailLayout(m.gridWidth())
+    if m.selCol < 0 || m.selCol >= len(starts) {
+        return
+    }
+    start := starts[m.selCol]
+    end := len(lines)
+    if m.selCol+1 < len(starts) {
+        end = starts[m.selCol+1]
+    }
+    h := m.detailHeight()
+    if start < m.detailScroll {
+        m.detailScroll = start
+    } else if end > m.detailScroll+h {
+        // show the whole field if it fits, otherwise its first lines
+        m.detailScroll = min(start, end-h)
+    }
+}
+
+// renderDetail draws the full-row detail view into b.
+func (m model) renderDetail(b *strings.Builder, width int) {
+    if m.jsonActive {
+        m.renderJSONView(b, width)
+        return
+    }
+    title := fmt.Sprintf("Record: %s (%s)", m.tables[m.cursor], m.pagePosition())
+    if m.previewRowIDs != nil && m.selRow < len(m.previewRowIDs) {
+        title += fmt.Sprintf(" rowid %d", m.previewRowIDs[m.selRow])
+    }
+    if m.editingActive { title += " " + stylePrompt.Render("EDITING") }
+    b.WriteString(styleHeader.Render(title) + "\n")
+    b.WriteString(styleDim.Render(fmt.Sprintf("j/k field, h/l record, %s edit, e $EDITOR, J json tree, B hex, %s copy, PgUp/PgDn scroll, Esc back", keyLabel(m.keys.key("edit")), keyLabel(m.keys.key("copy")))) + "\n")
+    lines := m.detailLines(width)
+    h := m.detailHeight() - 1
+    start := min(m.detailScroll, max(0, len(lines)-1))
+    for i := start; i < len(lines) && i < start+h; i++ {
+        b.WriteString(lines[i] + "\n")
+    }
+}
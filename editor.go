@@ -0,0 +1,229 @@
+package main
+
+import (
+    "strings"
+    "unicode"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// textArea is a small multi-line text editor. The text is kept as runes with a
+// single cursor offset; lines are derived on demand.
+type textArea struct {
+    buf []rune
+    pos int // cursor offset into buf
+}
+
+func (t *textArea) setValue(s string) {
+    t.buf = []rune(s)
+    t.pos = len(t.buf)
+}
+
+func (t textArea) value() string { return string(t.buf) }
+
+func (t *textArea) insert(s string) {
+    r := []rune(s)
+    out := make([]rune, 0, len(t.buf)+len(r))
+    out = append(out, t.buf[:t.pos]...)
+    out = append(out, r...)
+    out = append(out, t.buf[t.pos:]...)
+    t.buf = out
+    t.pos += len(r)
+}
+
+// lineCol returns the zero-based line and column of the cursor.
+func (t textArea) lineCol() (int, int) {
+    line, col := 0, 0
+    for i := 0; i < t.pos; i++ {
+        if t.buf[i] == '\n' {
+            line++
+            col = 0
+        } else {
+            col++
+        }
+    }
+    return line, col
+}
+
+// lineStart returns the offset of the first rune of the line containing p.
+func (t textArea) lineStart(p int) int {
+    for p > 0 && t.buf[p-1] != '\n' {
+        p--
+    }
+    return p
+}
+
+// lineEnd returns the offset of the newline (or end of text) ending the line containing p.
+func (t textArea) lineEnd(p int) int {
+    for p < len(t.buf) && t.buf[p] != '\n' {
+        p++
+    }
+    return p
+}
+
+// moveVertical moves the cursor up (-1) or down (+1) one line, keeping the column when possible.
+func (t *textArea) moveVertical(dir int) {
+    start := t.lineStart(t.pos)
+    col := t.pos - start
+    var target int
+    if dir < 0 {
+        if start == 0 {
+            t.pos = 0
+            return
+        }
+        target = t.lineStart(start - 1)
+    } else {
+        end := t.lineEnd(t.pos)
+        if end >= len(t.buf) {
+            t.pos = len(t.buf)
+            return
+        }
+        target = end + 1
+    }
+    t.pos = min(target+col, t.lineEnd(target))
+}
+
+// isWordRune reports whether r is part of a word for word-wise movement.
+func isWordRune(r rune) bool {
+    return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordLeft returns the offset of the start of the word before the cursor.
+func (t textArea) wordLeft() int {
+    p := t.pos
+    for p > 0 && !isWordRune(t.buf[p-1]) {
+        p--
+    }
+    for p > 0 && isWordRune(t.buf[p-1]) {
+        p--
+    }
+    return p
+}
+
+// wordRight returns the offset just past the end of the word after the cursor.
+func (t textArea) wordRight() int {
+    p := t.pos
+    for p < len(t.buf) && !isWordRune(t.buf[p]) {
+        p++
+    }
+    for p < len(t.buf) && isWordRune(t.buf[p]) {
+        p++
+    }
+    return p
+}
+
+// deleteRange removes buf[from:to] and leaves the cursor at from.
+func (t *textArea) deleteRange(from, to int) {
+    if from >= to {
+        return
+    }
+    t.buf = append(t.buf[:from], t.buf[to:]...)
+    t.pos = from
+}
+
+// handleKey applies an editing key. It reports whether the key was consumed.
+func (t *textArea) handleKey(msg tea.KeyMsg) bool {
+    if msg.Paste {
+        // bracketed paste: insert as-is, normalising line endings
+        s := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(string(msg.Runes))
+        t.insert(s)
+        return true
+    }
+    if msg.Alt {
+        // emacs-style word movement: alt+b / alt+f, alt+d, alt+backspace
+        switch {
+        case msg.Type == tea.KeyLeft || msg.Type == tea.KeyRunes && string(msg.Runes) == "b":
+            t.pos = t.wordLeft()
+            return true
+        case msg.Type == tea.KeyRight || msg.Type == tea.KeyRunes && string(msg.Runes) == "f":
+            t.pos = t.wordRight()
+            return true
+        case msg.Type == tea.KeyRunes && string(msg.Runes) == "d":
+            t.deleteRange(t.pos, t.wordRight())
+            return true
+        case msg.Type == tea.KeyBackspace:
+            t.deleteRange(t.wordLeft(), t.pos)
+            return true
+        case msg.Type == tea.KeyRunes:
+            return false
+        }
+    }
+    switch msg.Type {
+    case tea.KeyRunes, tea.KeySpace:
+        t.insert(string(msg.Runes))
+    case tea.KeyEnter:
+        t.insert("\n")
+    case tea.KeyBackspace:
+        if t.pos > 0 {
+            t.buf = append(t.buf[:t.pos-1], t.buf[t.pos:]...)
+            t.pos--
+        }
+    case tea.KeyDelete:
+        if t.pos < len(t.buf) {
+            t.buf = append(t.buf[:t.pos], t.buf[t.pos+1:]...)
+        }
+    case tea.KeyLeft:
+        if t.pos > 0 { t.pos-- }
+    case tea.KeyRight:
+        if t.pos < len(t.buf) { t.pos++ }
+    case tea.KeyUp:
+        t.moveVertical(-1)
+    case tea.KeyDown:
+        t.moveVertical(1)
+    case tea.KeyHome, tea.KeyCtrlA:
+        t.pos = t.lineStart(t.pos)
+    case tea.KeyEnd, tea.KeyCtrlE:
+        t.pos = t.lineEnd(t.pos)
+    case tea.KeyCtrlLeft:
+        t.pos = t.wordLeft()
+    case tea.KeyCtrlRight:
+        t.pos = t.wordRight()
+    case tea.KeyCtrlW:
+        t.deleteRange(t.wordLeft(), t.pos)
+    case tea.KeyCtrlK:
+        t.deleteRange(t.pos, t.lineEnd(t.pos))
+    case tea.KeyCtrlHome:
+        t.pos = 0
+    case tea.KeyCtrlEnd:
+        t.pos = len(t.buf)
+    default:
+        return false
+    }
+    return true
+}
+
+// render returns up to height display lines, each at most width columns, scrolled
+// so the cursor line is visible. The cursor is drawn in reverse video when focused.
+func (t textArea) render(width, height int, focused bool) []string {
+    lines := strings.Split(string(t.buf), "\n")
+    curLine, curCol := t.lineCol()
+    top := 0
+    if height > 0 && curLine >= height {
+        top = curLine - height + 1
+    }
+    var out []string
+    for i := top; i < len(lines) && (height <= 0 || len(out) < height); i++ {
+        r := []rune(lines[i])
+        left := 0
+        if i == curLine && curCol >= width {
+            // scroll long lines horizontally around the cursor
+            left = curCol - width + 1
+        }
+        if left > len(r) { left = len(r) }
+        vis := r[left:]
+        if i == curLine && focused {
+            c := curCol - left
+            before := string(vis[:min(c, len(vis))])
+            under := " "
+            after := ""
+            if c < len(vis) {
+                under = string(vis[c])
+                after = string(vis[c+1:])
+            }
+            out = append(out, truncateANSI(before+styleEditCursor.Render(under)+after, width))
+            continue
+        }
+        out = append(out, truncateCell(string(vis), width))
+    }
+    return out
+}
@@ -0,0 +1,492 @@
+package main
+
+import (
+    "bufio"
+    "database/sql"
+    "encoding/csv"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+// exportFormats lists the supported output formats. Exports to a file pick one
+// by extension; the command-line subcommands take --format, which also
+// accepts "table".
+var exportFormats = []string{"csv", "tsv", "json", "ndjson", "markdown", "sql", "table"}
+
+// formatForPath picks the export format from the file extension.
+func formatForPath(path string) (string, error) {
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".csv":
+        return "csv", nil
+    case ".tsv", ".tab":
+        return "tsv", nil
+    case ".json":
+        return "json", nil
+    case ".ndjson", ".jsonl":
+        return "ndjson", nil
+    case ".md", ".markdown":
+        return "markdown", nil
+    case ".sql":
+        return "sql", nil
+    }
+    return "", fmt.Errorf("unknown export format for %q (use .csv, .tsv, .json, .ndjson, .md or .sql)", path)
+}
+
+// rowWriter writes one result set in a particular format. header is called
+// once before the rows and close once after them.
+type rowWriter interface {
+    header(cols []string) error
+    row(vals []any) error
+    close() error
+}
+
+// newRowWriter returns the writer for format. table names the target of
+// INSERT statements in the sql format.
+func newRowWriter(format string, w io.Writer, table string) (rowWriter, error) {
+    switch format {
+    case "csv", "tsv":
+        cw := csv.NewWriter(w)
+        if format == "tsv" { cw.Comma = '\t' }
+        return &csvRowWriter{w: cw}, nil
+    case "json", "ndjson":
+        return &jsonRowWriter{w: w, lines: format == "ndjson"}, nil
+    case "markdown":
+        return &markdownRowWriter{w: w}, nil
+    case "sql":
+        return &insertRowWriter{w: w, table: table}, nil
+    case "table":
+        return &tableRowWriter{w: w}, nil
+    }
+    return nil, fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(exportFormats, ", "))
+}
+
+// writeRows streams every row of rows into rw and returns the number written.
+func writeRows(rw rowWriter, rows *sql.Rows) (int, error) {
+    cols, err := rows.Columns()
+    if err != nil {
+        return 0, err
+    }
+    if err := rw.header(cols); err != nil {
+        return 0, err
+    }
+    n := 0
+    vals := make([]any, len(cols))
+    dest := make([]any, len(cols))
+    for i := range vals {
+        dest[i] = &vals[i]
+    }
+    for rows.Next() {
+        if err := rows.Scan(dest...); err != nil {
+            return n, err
+        }
+        if err := rw.row(vals); err != nil {
+            return n, err
+        }
+        n++
+    }
+    if err := rows.Err(); err != nil {
+        return n, err
+    }
+    return n, rw.close()
+}
+
+// exportText renders a value as plain text: NULL becomes empty, text blobs are
+// kept as-is and binary blobs are hex encoded.
+func exportText(v any) string {
+    switch t := v.(type) {
+    case nil:
+        return ""
+    case []byte:
+        if utf8.Valid(t) {
+            return string(t)
+        }
+        return "0x" + hex.EncodeToString(t)
+    case time.Time:
+        return formatTime(t)
+    case float64:
+        return strconv.FormatFloat(t, 'g', -1, 64)
+    }
+    return fmt.Sprint(v)
+}
+
+type csvRowWriter struct {
+    w *csv.Writer
+}
+
+func (c *csvRowWriter) header(cols []string) error { return c.w.Write(cols) }
+
+func (c *csvRowWriter) row(vals []any) error {
+    rec := make([]string, len(vals))
+    for i, v := range vals {
+        rec[i] = exportText(v)
+    }
+    return c.w.Write(rec)
+}
+
+func (c *csvRowWriter) close() error {
+    c.w.Flush()
+    return c.w.Error()
+}
+
+// jsonRowWriter writes a JSON array of objects, or one object per line when
+// lines is set. Keys keep the column order.
+type jsonRowWriter struct {
+    w     io.Writer
+    lines bool
+    keys  [][]byte
+    n     int
+}
+
+func (j *jsonRowWriter) header(cols []string) error {
+    j.keys = make([][]byte, len(cols))
+    for i, c := range cols {
+        k, err := json.Marshal(c)
+        if err != nil {
+            return err
+        }
+        j.keys[i] = k
+    }
+    if j.lines {
+        return nil
+    }
+    _, err := io.WriteString(j.w, "[")
+    return err
+}
+
+func (j *jsonRowWriter) row(vals []any) error {
+    var b strings.Builder
+    if !j.lines {
+        if j.n > 0 { b.WriteString(",") }
+        b.WriteString("\n  ")
+    }
+    b.WriteString("{")
+    for i, v := range vals {
+        if i > 0 { b.WriteString(", ") }
+        b.Write(j.keys[i])
+        b.WriteString(": ")
+        enc, err := json.Marshal(jsonValue(v))
+        if err != nil {
+            return err
+        }
+        b.Write(enc)
+    }
+    b.WriteString("}")
+    if j.lines { b.WriteString("\n") }
+    j.n++
+    _, err := io.WriteString(j.w, b.String())
+    return err
+}
+
+func (j *jsonRowWriter) close() error {
+    if j.lines {
+        return nil
+    }
+    end := "]\n"
+    if j.n > 0 { end = "\n]\n" }
+    _, err := io.WriteString(j.w, end)
+    return err
+}
+
+// jsonValue maps a database value onto its JSON form; binary blobs become
+// base64 strings via encoding/json's []byte handling.
+func jsonValue(v any) any {
+    switch t := v.(type) {
+    case []byte:
+        if utf8.Valid(t) {
+            return string(t)
+        }
+    case time.Time:
+        return formatTime(t)
+    }
+    return v
+}
+
+type markdownRowWriter struct {
+    w io.Writer
+}
+
+func (md *markdownRowWriter) header(cols []string) error {
+    seps := make([]string, len(cols))
+    for i := range seps {
+        seps[i] = "---"
+    }
+    return md.line(cols, seps)
+}
+
+func (md *markdownRowWriter) row(vals []any) error {
+    cells := make([]string, len(vals))
+    for i, v := range vals {
+        if v == nil {
+            cells[i] = "NULL"
+        } else {
+            cells[i] = exportText(v)
+        }
+    }
+    return md.line(cells)
+}
+
+func (md *markdownRowWriter) line(rows ...[]string) error {
+    esc := strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+    for _, cells := range rows {
+        out := make([]string, len(cells))
+        for i, c := range cells {
+            out[i] = esc.Replace(c)
+        }
+        if _, err := io.WriteString(md.w, "| "+strings.Join(out, " | ")+" |\n"); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (md *markdownRowWriter) close() error { return nil }
+
+// tableRowWriter lines values up in padded columns under a header rule, like
+// the sqlite3 shell's column mode. Rows are buffered to size the columns.
+type tableRowWriter struct {
+    w    io.Writer
+    rows [][]string
+}
+
+func (t *tableRowWriter) header(cols []string) error {
+    t.rows = append(t.rows, cols)
+    return nil
+}
+
+func (t *tableRowWriter) row(vals []any) error {
+    esc := strings.NewReplacer("\r", "\\r", "\n", "\\n", "\t", "\\t")
+    cells := make([]string, len(vals))
+    for i, v := range vals {
+        if v == nil {
+            cells[i] = "NULL"
+        } else {
+            cells[i] = esc.Replace(exportText(v))
+        }
+    }
+    t.rows = append(t.rows, cells)
+    return nil
+}
+
+func (t *tableRowWriter) close() error {
+    if len(t.rows) == 0 {
+        return nil
+    }
+    widths := make([]int, len(t.rows[0]))
+    for _, r := range t.rows {
+        for i, c := range r {
+            widths[i] = max(widths[i], visibleWidth(c))
+        }
+    }
+    rule := make([]string, len(widths))
+    for i, w := range widths {
+        rule[i] = strings.Repeat("-", w)
+    }
+    lines := append([][]string{t.rows[0], rule}, t.rows[1:]...)
+    for _, r := range lines {
+        var b strings.Builder
+        for i, c := range r {
+            if i > 0 { b.WriteString("  ") }
+            b.WriteString(c)
+            if i < len(r)-1 { b.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(c))) }
+        }
+        b.WriteString("\n")
+        if _, err := io.WriteString(t.w, b.String()); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// insertRowWriter writes one INSERT statement per row, wrapped in a transaction.
+type insertRowWriter struct {
+    w      io.Writer
+    table  string
+    prefix string
+}
+
+func (s *insertRowWriter) header(cols []string) error {
+    s.prefix = fmt.Sprintf("INSERT INTO %s (%s) VALUES (", quoteIdent(s.table), quoteIdentList(cols))
+    _, err := io.WriteString(s.w, "BEGIN;\n")
+    return err
+}
+
+func (s *insertRowWriter) row(vals []any) error {
+    lits := make([]string, len(vals))
+    for i, v := range vals {
+        lits[i] = sqlLiteral(v)
+    }
+    _, err := io.WriteString(s.w, s.prefix+strings.Join(lits, ", ")+");\n")
+    return err
+}
+
+func (s *insertRowWriter) close() error {
+    _, err := io.WriteString(s.w, "COMMIT;\n")
+    return err
+}
+
+// sqlLiteral renders v as an SQLite literal.
+func sqlLiteral(v any) string {
+    switch t := v.(type) {
+    case nil:
+        return "NULL"
+    case int64:
+        return strconv.FormatInt(t, 10)
+    case float64:
+        s := strconv.FormatFloat(t, 'g', -1, 64)
+        if !strings.ContainsAny(s, ".eEn") { s += ".0" } // keep REAL affinity
+        return s
+    case bool:
+        if t { return "1" }
+        return "0"
+    case []byte:
+        return "X'" + hex.EncodeToString(t) + "'"
+    case time.Time:
+        return sqlString(formatTime(t))
+    }
+    return sqlString(fmt.Sprint(v))
+}
+
+func sqlString(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+
+// exportQuery builds the statement that streams the whole previewed table or
+// view with the active filters and sort applied. Columns are read raw so
+// DATETIME text is written as stored; the virtual JSON columns are added when
+// computed is set.
+func (m *model) exportQuery(computed bool) (string, []any) {
+    var cols []string
+    for _, c := range m.tableCols {
+        cols = append(cols, c.Name)
+    }
+    if len(cols) == 0 {
+        cols = m.tableColumns()
+    }
+    exprs := make([]string, len(cols))
+    for i, c := range cols {
+        exprs[i] = rawExpr(c) + " AS " + quoteIdent(c)
+    }
+    if computed {
+        exprs = append(exprs, m.jsonSelectExprs()...)
+    }
+    q := fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), quoteIdent(m.previewTable))
+    where, params := m.filterWhere()
+    if len(where) > 0 {
+        q += " WHERE " + strings.Join(where, " AND ")
+    }
+    dir := m.sortDirection(false)
+    var order []string
+    if m.sortCol != "" {
+        order = append(order, quoteIdent(m.sortCol)+" "+dir)
+    }
+    for _, k := range m.keyExprs() {
+        order = append(order, k+" "+dir)
+    }
+    if len(order) > 0 {
+        q += " ORDER BY " + strings.Join(order, ", ")
+    }
+    return q, params
+}
+
+// rerunnable reports whether q can safely be executed again to stream its
+// full result: plain reads only, nothing that could change data.
+func rerunnable(q string) bool {
+    words := sqlWords(q)
+    switch statementVerb(words) {
+    case "SELECT", "VALUES":
+        return !hasWord(words, "RETURNING")
+    }
+    return false
+}
+
+// exportNote warns when a query result export could not include every row:
+// the statement isn't run again and the pane kept only the first rows.
+func (m model) exportNote(fromQuery bool) string {
+    res := m.queryResult
+    if !fromQuery || res == nil || !res.Truncated || rerunnable(res.SQL) {
+        return ""
+    }
+    return fmt.Sprintf("; truncated to the first %d rows, the statement is not run again", maxQueryRows)
+}
+
+// exportToFile writes the previewed table (fromQuery false) or the latest
+// query result to path, in the format given by its extension. The rows go to
+// a temp file next to path that replaces it only once everything is written,
+// so a failed export leaves an existing file alone (the prompt asks before
+// getting here).
+func (m *model) exportToFile(path string, fromQuery bool) (n int, err error) {
+    format, err := formatForPath(path)
+    if err != nil {
+        return 0, err
+    }
+    mode := os.FileMode(0o644)
+    if st, err := os.Stat(path); err == nil {
+        mode = st.Mode().Perm()
+    }
+    f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+    if err != nil {
+        return 0, err
+    }
+    defer func() {
+        if cerr := f.Close(); err == nil { err = cerr }
+        if err == nil { err = os.Chmod(f.Name(), mode) }
+        if err == nil { err = os.Rename(f.Name(), path) }
+        if err != nil { _ = os.Remove(f.Name()) }
+    }()
+    bw := bufio.NewWriter(f)
+    table := m.previewTable
+    if fromQuery { table = "query_result" }
+    rw, err := newRowWriter(format, bw, table)
+    if err != nil {
+        return 0, err
+    }
+    if !fromQuery {
+        // INSERT statements can only name real columns
+        q, params := m.exportQuery(format != "sql")
+        rows, err := m.conn().Query(q, params...)
+        if err != nil {
+            return 0, err
+        }
+        defer rows.Close()
+        if n, err = writeRows(rw, rows); err != nil {
+            return n, err
+        }
+        return n, bw.Flush()
+    }
+    res := m.queryResult
+    if res == nil || !res.IsSelect || res.Err != nil {
+        return 0, fmt.Errorf("no query result to export")
+    }
+    if rerunnable(res.SQL) {
+        rows, err := m.conn().Query(res.SQL)
+        if err != nil {
+            return 0, err
+        }
+        defer rows.Close()
+        if n, err = writeRows(rw, rows); err != nil {
+            return n, err
+        }
+        return n, bw.Flush()
+    }
+    // statements with side effects are not run again: write the values we
+    // kept, which stop at maxQueryRows (see exportNote)
+    if err := rw.header(res.Columns); err != nil {
+        return 0, err
+    }
+    for _, vals := range res.Values {
+        if err := rw.row(vals); err != nil {
+            return n, err
+        }
+        n++
+    }
+    if err := rw.close(); err != nil {
+        return n, err
+    }
+    return n, bw.Flush()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+const exportSchema = "CREATE TABLE t(id INTEGER PRIMARY KEY, v TEXT); INSERT INTO t VALUES (1, 'a'), (2, 'b')"
+
+func readFile(t *testing.T, path string) string {
+    t.Helper()
+    b, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return string(b)
+}
+
+func TestExportAsksBeforeOverwriting(t *testing.T) {
+    m := newTestModel(t, options{table: "t"}, exportSchema)
+    path := filepath.Join(t.TempDir(), "out.csv")
+    if err := os.WriteFile(path, []byte("keep"), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    m.runPrompt("export", path)
+    if !m.promptActive || m.promptKind != "export-overwrite" {
+        t.Fatalf("no overwrite prompt: %q", m.status)
+    }
+    m.runPrompt("export-overwrite", "n")
+    if got := readFile(t, path); got != "keep" {
+        t.Errorf("declined overwrite changed the file to %q", got)
+    }
+    m.runPrompt("export", path)
+    m.runPrompt("export-overwrite", "y")
+    if got := readFile(t, path); got != "id,v\n1,a\n2,b\n" {
+        t.Errorf("file = %q", got)
+    }
+    if st, err := os.Stat(path); err != nil || st.Mode().Perm() != 0o600 {
+        t.Errorf("mode = %v, %v, want the original 0600", st.Mode().Perm(), err)
+    }
+}
+
+func TestFailedExportKeepsFile(t *testing.T) {
+    m := newTestModel(t, options{table: "t"}, exportSchema)
+    dir := t.TempDir()
+    path := filepath.Join(dir, "out.csv")
+    if err := os.WriteFile(path, []byte("keep"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    m.queryResult = &queryResult{SQL: "SELECT * FROM missing", IsSelect: true}
+    if _, err := m.exportToFile(path, true); err == nil {
+        t.Fatal("export of a failing query succeeded")
+    }
+    if got := readFile(t, path); got != "keep" {
+        t.Errorf("failed export changed the file to %q", got)
+    }
+    if ents, _ := os.ReadDir(dir); len(ents) != 1 {
+        t.Errorf("temp files left behind: %v", ents)
+    }
+}
+
+func TestExportDoesNotRerunWrites(t *testing.T) {
+    m := newTestModel(t, options{table: "t"}, exportSchema)
+    res := runQuery(m.conn(), "INSERT INTO t(v) VALUES ('c'), (NULL) RETURNING id, v", 1)
+    if res.Err != nil {
+        t.Fatal(res.Err)
+    }
+    m.queryResult = &res
+    path := filepath.Join(t.TempDir(), "out.ndjson")
+    m.export(path, true)
+    if got := countRows(t, &m, "t"); got != 4 {
+        t.Fatalf("t has %d rows after the export, want 4 (the INSERT ran again?)", got)
+    }
+    if got := readFile(t, path); got != "{\"id\": 3, \"v\": \"c\"}\n" {
+        t.Errorf("file = %q, want the kept row with its stored value", got)
+    }
+    if !strings.Contains(m.status, "truncated to the first") {
+        t.Errorf("status = %q, want the truncation reported", m.status)
+    }
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorDoneMsg reports that the external editor exited. It carries the cell
+// it was opened for so a stale result can't land on a different cell.
+type editorDoneMsg struct {
+    path     string
+    original string
+    table    string
+    row      int
+    col      int
+    err      error
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR (which may
+// include arguments, e.g. "code -w"), falling back to vi.
+func editorCommand(path string) *exec.Cmd {
+    ed := os.Getenv("VISUAL")
+    if ed == "" {
+        ed = os.Getenv("EDITOR")
+    }
+    args := strings.Fields(ed)
+    if len(args) == 0 {
+        args = []string{"vi"}
+    }
+    return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// editorExtension picks a temp-file extension from the value so the editor
+// can highlight it: .json for JSON documents, .sql for SQL, .txt otherwise.
+func editorExtension(s string) string {
+    t := strings.TrimSpace(s)
+    if (strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[")) && json.Valid([]byte(t)) {
+        return ".json"
+    }
+    switch strings.ToUpper(firstKeyword(t)) {
+    case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH", "CREATE", "ALTER", "DROP", "REPLACE", "PRAGMA":
+        return ".sql"
+    }
+    return ".txt"
+}
+
+// openExternalEditor writes the selected cell to a temp file and suspends the
+// program while $EDITOR runs on it.
+func (m *model) openExternalEditor() tea.Cmd {
+    cur, ok := m.selectedCell()
+    if !ok || m.denyWrite("editing") {
+        return nil
+    }
+    if col := m.previewColumns[m.selCol]; m.isJSONColumn(col) {
+        m.status = fmt.Sprintf("%s is a computed JSON column and can't be edited", col)
+        return nil
+    }
+    val := m.editSeed(cur)
+    f, err := os.CreateTemp("", appName+"-*"+editorExtension(val))
+    if err != nil {
+        m.status = fmt.Sprintf("editor error: %v", err)
+        return nil
+    }
+    _, werr := f.WriteString(val)
+    if cerr := f.Close(); werr == nil {
+        werr = cerr
+    }
+    if werr != nil {
+        _ = os.Remove(f.Name())
+        m.status = fmt.Sprintf("editor error: %v", werr)
+        return nil
+    }
+    done := editorDoneMsg{path: f.Name(), original: val, table: m.previewTable, row: m.selRow, col: m.selCol}
+    return tea.ExecProcess(editorCommand(f.Name()), func(err error) tea.Msg {
+        done.err = err
+        return done
+    })
+}
+
+// finishExternalEdit reads the edited file back and saves it through
+// commitCellEdit. Nothing is written when the contents are unchanged.
+func (m *model) finishExternalEdit(msg editorDoneMsg) {
+    defer os.Remove(msg.path)
+    if msg.err != nil {
+        m.status = fmt.Sprintf("editor error: %v", msg.err)
+        return
+    }
+    b, err := os.ReadFile(msg.path)
+    if err != nil {
+        m.status = fmt.Sprintf("editor error: %v", err)
+        return
+    }
+    val := string(b)
+    // most editors add a final newline; don't count that as a change
+    if !strings.HasSuffix(msg.original, "\n") {
+        val = strings.TrimSuffix(strings.TrimSuffix(val, "\n"), "\r")
+    }
+    if val == msg.original {
+        m.status = "unchanged, nothing written"
+        return
+    }
+    if msg.table != m.previewTable || msg.row != m.selRow || msg.col != m.selCol {
+        m.status = "selection changed while editing; edit discarded"
+        return
+    }
+    m.cellEditor.setValue(val)
+    err = m.commitCellEdit()
+    var verr validationError
+    if errors.As(err, &verr) {
+        // let the user fix the value in the built-in editor
+        m.editingActive = true
+        m.editErr = verr.msg
+        m.status = fmt.Sprintf("invalid value: %v", verr)
+        return
+    }
+    m.cellEditor.setValue("")
+    if err != nil {
+        m.status = fmt.Sprintf("update error: %v", err)
+    } else {
+        m.status = "updated from editor"
+    }
+    m.refreshPreview()
+}
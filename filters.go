@@ -0,0 +1,129 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+)
+
+// columnFilter is one row condition applied to the preview, e.g. age > 10.
+type columnFilter struct {
+    Col   string
+    Op    string // normalized operator: =, !=, <, <=, >, >=, LIKE, NOT LIKE, GLOB, IS NULL, IS NOT NULL
+    Value string // bound as a parameter; unused for IS [NOT] NULL
+    Raw   any    // when set, bound instead of Value: a stored key value, e.g. a blob
+}
+
+// filterOps lists the accepted operators, longest first so prefixes don't shadow them.
+var filterOps = []string{"IS NOT NULL", "IS NULL", "NOT LIKE", "LIKE", "GLOB", ">=", "<=", "!=", "<>", "=", ">", "<"}
+
+// parseFilter turns user input such as "> 10", "LIKE %foo%" or "IS NULL" into a
+// filter on col. Input without an operator is treated as equality.
+func parseFilter(col, input string) (columnFilter, error) {
+    in := strings.TrimSpace(input)
+    if in == "" {
+        return columnFilter{}, fmt.Errorf("empty condition")
+    }
+    upper := strings.ToUpper(in)
+    for _, op := range filterOps {
+        if !strings.HasPrefix(upper, op) {
+            continue
+        }
+        rest := in[len(op):]
+        isWord := op[0] >= 'A' && op[0] <= 'Z'
+        if isWord && rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+            // e.g. "LIKEly" is a value, not the LIKE operator
+            continue
+        }
+        rest = strings.TrimSpace(rest)
+        if op == "<>" {
+            op = "!="
+        }
+        if op == "IS NULL" || op == "IS NOT NULL" {
+            if rest != "" {
+                return columnFilter{}, fmt.Errorf("unexpected %q after %s", rest, op)
+            }
+            return columnFilter{Col: col, Op: op}, nil
+        }
+        if rest == "" {
+            return columnFilter{}, fmt.Errorf("missing value after %s", op)
+        }
+        return columnFilter{Col: col, Op: op, Value: unquoteValue(rest)}, nil
+    }
+    return columnFilter{Col: col, Op: "=", Value: unquoteValue(in)}, nil
+}
+
+// unquoteValue strips one pair of matching single or double quotes.
+func unquoteValue(s string) string {
+    if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+        q := string(s[0])
+        return strings.ReplaceAll(s[1:len(s)-1], q+q, q)
+    }
+    return s
+}
+
+// sql returns the parameterized condition for f.
+func (f columnFilter) sql() (string, []any) {
+    if f.Op == "IS NULL" || f.Op == "IS NOT NULL" {
+        return fmt.Sprintf("%s %s", quoteIdent(f.Col), f.Op), nil
+    }
+    if f.Raw != nil {
+        return fmt.Sprintf("%s %s ?", quoteIdent(f.Col), f.Op), []any{f.Raw}
+    }
+    return fmt.Sprintf("%s %s ?", quoteIdent(f.Col), f.Op), []any{f.Value}
+}
+
+// label is the chip text shown above the grid.
+func (f columnFilter) label() string {
+    if f.Value == "" && (f.Op == "IS NULL" || f.Op == "IS NOT NULL") {
+        return f.Col + " " + f.Op
+    }
+    return fmt.Sprintf("%s %s %s", f.Col, f.Op, f.Value)
+}
+
+// filterWhere combines the active preview filters into WHERE terms and params.
+func (m *model) filterWhere() ([]string, []any) {
+    var terms []string
+    var params []any
+    for _, f := range m.filters {
+        t, p := f.sql()
+        terms = append(terms, t)
+        params = append(params, p...)
+    }
+    return terms, params
+}
+
+// addFilter applies a new condition and reloads the preview from the top.
+func (m *model) addFilter(f columnFilter) {
+    m.filters = append(m.filters, f)
+    m.resetPage()
+    m.refreshPreview()
+}
+
+// removeFilter drops the latest filter on col, or the latest filter overall
+// when col has none. It reports the removed filter.
+func (m *model) removeFilter(col string) (columnFilter, bool) {
+    if len(m.filters) == 0 {
+        return columnFilter{}, false
+    }
+    idx := len(m.filters) - 1
+    for i := len(m.filters) - 1; i >= 0; i-- {
+        if strings.EqualFold(m.filters[i].Col, col) {
+            idx = i
+            break
+        }
+    }
+    removed := m.filters[idx]
+    m.filters = append(m.filters[:idx:idx], m.filters[idx+1:]...)
+    m.resetPage()
+    m.refreshPreview()
+    return removed, true
+}
+
+// renderFilterChips returns the active filters as a line of chips.
+func (m model) renderFilterChips() string {
+    chips := make([]string, len(m.filters))
+    for i, f := range m.filters {
+        chips[i] = styleChip.Render(f.label())
+    }
+    return strings.Join(chips, " ")
+}
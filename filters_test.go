@@ -0,0 +1,79 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestParseFilter(t *testing.T) {
+    tests := []struct {
+        in      string
+        op      string
+        value   string
+        wantErr bool
+    }{
+        {"42", "=", "42", false},
+        {"> 10", ">", "10", false},
+        {">=10", ">=", "10", false},
+        {"<> x", "!=", "x", false},
+        {"like %foo%", "LIKE", "%foo%", false},
+        {"NOT LIKE 'a b'", "NOT LIKE", "a b", false},
+        {"glob *.txt", "GLOB", "*.txt", false},
+        {"is null", "IS NULL", "", false},
+        {"  IS NOT NULL ", "IS NOT NULL", "", false},
+        {"likely", "=", "likely", false},
+        {"'it''s'", "=", "it's", false},
+        {"\"quoted\"", "=", "quoted", false},
+        {"", "", "", true},
+        {">", "", "", true},
+        {"IS NULL x", "", "", true},
+    }
+    for _, tt := range tests {
+        f, err := parseFilter("c", tt.in)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("parseFilter(%q) = %+v, want an error", tt.in, f)
+            }
+            continue
+        }
+        if err != nil || f.Op != tt.op || f.Value != tt.value || f.Col != "c" {
+            t.Errorf("parseFilter(%q) = %+v, %v, want %s %q", tt.in, f, err, tt.op, tt.value)
+        }
+    }
+}
+
+func TestFilterSQL(t *testing.T) {
+    tests := []struct {
+        f      columnFilter
+        want   string
+        params []any
+    }{
+        {columnFilter{Col: "a b", Op: ">", Value: "1"}, `"a b" > ?`, []any{"1"}},
+        {columnFilter{Col: "a", Op: "IS NULL"}, `"a" IS NULL`, nil},
+        {columnFilter{Col: "a", Op: "=", Value: "x'01'", Raw: []byte{1}}, `"a" = ?`, []any{[]byte{1}}},
+    }
+    for _, tt := range tests {
+        got, params := tt.f.sql()
+        if got != tt.want || !reflect.DeepEqual(params, tt.params) {
+            t.Errorf("%+v.sql() = %s %v, want %s %v", tt.f, got, params, tt.want, tt.params)
+        }
+    }
+}
+
+func TestFiltersSurviveSortCycle(t *testing.T) {
+    m := newTestModel(t, options{table: "t", pageSize: 10},
+        "CREATE TABLE t(id INTEGER PRIMARY KEY, v INTEGER)",
+        "INSERT INTO t VALUES (1, 5), (2, 15), (3, 25)")
+    f, err := parseFilter("v", "> 10")
+    if err != nil {
+        t.Fatal(err)
+    }
+    m.addFilter(f)
+    // ascending, descending, then off again
+    for i := 0; i < 3; i++ {
+        m.cycleSort("v")
+        if len(m.filters) != 1 || m.totalRows != 2 || len(m.preview) != 2 {
+            t.Fatalf("after %d sort change(s): %d filter(s), %d of %d row(s)", i+1, len(m.filters), len(m.preview), m.totalRows)
+        }
+    }
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// navEntry remembers a preview position so foreign key jumps can go back.
+type navEntry struct {
+    table      string
+    filters    []columnFilter
+    sortCol    string
+    sortDesc   bool
+    pageStart  []any
+    pageOffset int
+    selRow     int
+    selCol     int
+}
+
+// refTarget is one child table referencing the selected row.
+type refTarget struct {
+    fk      foreignKey
+    filters []columnFilter // child columns = selected row's values
+    count   int
+}
+
+func (m *model) pushNav() {
+    if m.previewTable == "" {
+        return
+    }
+    m.navStack = append(m.navStack, navEntry{
+        table:      m.previewTable,
+        filters:    append([]columnFilter(nil), m.filters...),
+        sortCol:    m.sortCol,
+        sortDesc:   m.sortDesc,
+        pageStart:  m.pageStart,
+        pageOffset: m.pageOffset,
+        selRow:     m.selRow,
+        selCol:     m.selCol,
+    })
+}
+
+// popNav returns to the position saved before the last jump.
+func (m *model) popNav() bool {
+    if len(m.navStack) == 0 {
+        return false
+    }
+    e := m.navStack[len(m.navStack)-1]
+    m.navStack = m.navStack[:len(m.navStack)-1]
+    if !m.selectTable(e.table) {
+        m.status = fmt.Sprintf("table %s no longer exists", e.table)
+        return true
+    }
+    m.filters = e.filters
+    m.sortCol, m.sortDesc = e.sortCol, e.sortDesc
+    m.pageStart, m.pageOffset = e.pageStart, e.pageOffset
+    m.refreshPreview()
+    m.selRow, m.selCol = e.selRow, e.selCol
+    m.clampSelection()
+    m.scrollToSelCol()
+    return true
+}
+
+// selectTable moves the cursor to table and loads it from a clean state,
+// clearing the table search if it hides the target.
+func (m *model) selectTable(table string) bool {
+    idx := -1
+    for i, t := range m.tables {
+        if t == table { idx = i; break }
+    }
+    if idx < 0 && m.searchQuery != "" {
+        m.searchQuery = ""
+        m.searchActive = false
+        m.applyFilter()
+        for i, t := range m.tables {
+            if t == table { idx = i; break }
+        }
+    }
+    if idx < 0 {
+        return false
+    }
+    m.cursor = idx
+    m.focusPreview = true
+    m.detailActive = false
+    m.previewTable = "" // force a reset even when jumping within the same table
+    m.refreshPreview()
+    return true
+}
+
+// jumpTo opens table filtered by filters, remembering the current position.
+func (m *model) jumpTo(table string, filters []columnFilter) error {
+    m.pushNav()
+    if !m.selectTable(table) {
+        m.navStack = m.navStack[:len(m.navStack)-1]
+        return fmt.Errorf("table %s not found", table)
+    }
+    m.filters = filters
+    m.resetPage()
+    m.refreshPreview()
+    if len(filters) > 0 {
+        if idx := findColIndex(m.previewColumns, filters[0].Col); idx >= 0 {
+            m.selCol = idx
+        }
+    }
+    m.scrollToSelCol()
+    return nil
+}
+
+// primaryKeyNames returns the PK columns of table in PK order.
+func (m *model) primaryKeyNames(table string) ([]string, error) {
+    cols, err := getTableInfo(m.conn(), table)
+    if err != nil {
+        return nil, err
+    }
+    names := make([]string, 0, len(cols))
+    for k := 1; ; k++ {
+        found := false
+        for _, c := range cols {
+            if c.PKOrder == k { names = append(names, c.Name); found = true }
+        }
+        if !found { break }
+    }
+    return names, nil
+}
+
+// fkTargetColumns resolves the parent columns of fk, filling in the parent's
+// primary key where the constraint leaves them implicit.
+func (m *model) fkTargetColumns(fk foreignKey) ([]string, error) {
+    to := append([]string(nil), fk.To...)
+    for _, c := range to {
+        if c != "" {
+            continue
+        }
+        pk, err := m.primaryKeyNames(fk.RefTable)
+        if err != nil {
+            return nil, err
+        }
+        if len(pk) != len(to) {
+            return nil, fmt.Errorf("cannot resolve primary key of %s", fk.RefTable)
+        }
+        return pk, nil
+    }
+    return to, nil
+}
+
+// rowValue reads the selected row's stored value of column name, unformatted,
+// so keys match exactly (nil is SQL NULL).
+func (m *model) rowValue(name string) (any, error) {
+    loc, ok := m.rowLocator(m.selRow)
+    if !ok {
+        return nil, fmt.Errorf("the selected row has no key")
+    }
+    return readCell(m.conn(), m.previewTable, loc, name)
+}
+
+// keyFilter matches col against a stored key value.
+func keyFilter(col string, v any) columnFilter {
+    return columnFilter{Col: col, Op: "=", Value: formatValue(v), Raw: v}
+}
+
+// followForeignKey jumps from the selected foreign key cell to its parent row.
+func (m *model) followForeignKey() error {
+    if m.db == nil || m.previewTable == "" {
+        return fmt.Errorf("no table selected")
+    }
+    if m.selCol < 0 || m.selCol >= len(m.previewColumns) || m.selRow >= len(m.preview) {
+        return fmt.Errorf("no cell selected")
+    }
+    col := m.previewColumns[m.selCol]
+    fks, err := getForeignKeys(m.conn(), m.previewTable)
+    if err != nil {
+        return err
+    }
+    for _, fk := range fks {
+        if findColIndex(fk.From, col) < 0 {
+            continue
+        }
+        to, err := m.fkTargetColumns(fk)
+        if err != nil {
+            return err
+        }
+        filters := make([]columnFilter, len(fk.From))
+        for i, from := range fk.From {
+            v, err := m.rowValue(from)
+            if err != nil {
+                return err
+            }
+            if v == nil {
+                return fmt.Errorf("%s is NULL", from)
+            }
+            filters[i] = keyFilter(to[i], v)
+        }
+        return m.jumpTo(fk.RefTable, filters)
+    }
+    return fmt.Errorf("%s is not a foreign key column", col)
+}
+
+// loadReferences lists the child tables referencing the selected row along
+// with how many of their rows point at it.
+func (m *model) loadReferences() error {
+    if m.db == nil || m.previewTable == "" || m.selRow >= len(m.preview) {
+        return fmt.Errorf("no row selected")
+    }
+    incoming, err := getIncomingForeignKeys(m.conn(), m.previewTable)
+    if err != nil {
+        return err
+    }
+    var refs []refTarget
+    skipped := 0
+    for _, fk := range incoming {
+        to, err := m.fkTargetColumns(fk)
+        if err != nil {
+            return err
+        }
+        ref := refTarget{fk: fk}
+        var terms []string
+        var params []any
+        for i, from := range fk.From {
+            v, err := m.rowValue(to[i])
+            if err != nil {
+                return err
+            }
+            if v == nil {
+                break // a NULL key can't be referenced
+            }
+            f := keyFilter(from, v)
+            ref.filters = append(ref.filters, f)
+            t, p := f.sql()
+            terms = append(terms, t)
+            params = append(params, p...)
+        }
+        if len(ref.filters) < len(fk.From) {
+            skipped++
+            continue
+        }
+        q := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteIdent(fk.Table), strings.Join(terms, " AND "))
+        if err := m.conn().QueryRow(q, params...).Scan(&ref.count); err != nil {
+            return err
+        }
+        refs = append(refs, ref)
+    }
+    m.refs = refs
+    m.refsCursor = 0
+    m.refsActive = true
+    if skipped > 0 {
+        m.status = fmt.Sprintf("skipped %d foreign key(s) whose referenced columns are NULL", skipped)
+    }
+    return nil
+}
+
+// updateRefs handles keys in the "who references this row" list.
+func (m model) updateRefs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    switch m.actionFor(msg.String(), "Referencing rows", "Any view") {
+    case "quit":
+        return m.quit()
+    case "back":
+        m.refsActive = false
+    case "up":
+        if m.refsCursor > 0 { m.refsCursor-- }
+    case "down":
+        if m.refsCursor+1 < len(m.refs) { m.refsCursor++ }
+    case "open":
+        if m.refsCursor < len(m.refs) {
+            ref := m.refs[m.refsCursor]
+            m.refsActive = false
+            if err := m.jumpTo(ref.fk.Table, ref.filters); err != nil {
+                m.status = fmt.Sprintf("jump error: %v", err)
+            } else {
+                m.status = fmt.Sprintf("%d %s row(s) reference it (b to go back)", m.totalRows, ref.fk.Table)
+            }
+        }
+    }
+    return m, nil
+}
+
+// renderRefs draws the referencing tables list into b.
+func (m model) renderRefs(b *strings.Builder, width int) {
+    b.WriteString(styleHeader.Render(fmt.Sprintf("Rows referencing %s %s (Enter open, Esc back)", m.previewTable, m.pagePosition())) + "\n")
+    if len(m.refs) == 0 {
+        b.WriteString(styleDim.Render("no tables reference "+m.previewTable) + "\n")
+        return
+    }
+    for i, ref := range m.refs {
+        cursor := "  "
+        if i == m.refsCursor {
+            cursor = styleCursor.Render("> ")
+        }
+        line := fmt.Sprintf("%-8s %s", fmt.Sprintf("%d rows", ref.count), describeFK(ref.fk))
+        if ref.count == 0 {
+            line = styleDim.Render(line)
+        }
+        b.WriteString(cursor + truncateANSI(line, max(1, width-2)) + "\n")
+    }
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+// blob keys show as hex text in the grid, so they only match when the
+// stored bytes are bound
+const fkSchema = `
+CREATE TABLE parent(id BLOB PRIMARY KEY, code TEXT UNIQUE);
+CREATE TABLE child(id INTEGER PRIMARY KEY, pid BLOB REFERENCES parent(id));
+CREATE TABLE tagged(id INTEGER PRIMARY KEY, code TEXT REFERENCES parent(code));
+INSERT INTO parent VALUES (x'00ff', NULL), (x'0102', 'b');
+INSERT INTO child VALUES (1, x'00ff'), (2, x'00ff'), (3, x'0102');
+`
+
+func TestFollowForeignKeyMatchesStoredValue(t *testing.T) {
+    m := newTestModel(t, options{table: "child"}, fkSchema)
+    m.selCol = findColIndex(m.previewColumns, "pid")
+    if err := m.followForeignKey(); err != nil {
+        t.Fatal(err)
+    }
+    if m.previewTable != "parent" || m.totalRows != 1 {
+        t.Fatalf("jumped to %s with %d row(s), want the one parent row", m.previewTable, m.totalRows)
+    }
+    if !m.popNav() || m.previewTable != "child" {
+        t.Errorf("back went to %s", m.previewTable)
+    }
+}
+
+func TestLoadReferences(t *testing.T) {
+    m := newTestModel(t, options{table: "parent"}, fkSchema)
+    m.selRow = 0 // x'00ff', whose code is NULL
+    if err := m.loadReferences(); err != nil {
+        t.Fatal(err)
+    }
+    if len(m.refs) != 1 || m.refs[0].fk.Table != "child" || m.refs[0].count != 2 {
+        t.Fatalf("refs = %+v, want 2 child rows", m.refs)
+    }
+    if !strings.Contains(m.status, "skipped 1 foreign key") {
+        t.Errorf("status = %q, want the NULL code reported as skipped", m.status)
+    }
+    r, _ := m.updateRefs(keyMsg("enter"))
+    if got := r.(model); got.previewTable != "child" || got.totalRows != 2 {
+        t.Errorf("opened %s with %d row(s), want 2 child rows", got.previewTable, got.totalRows)
+    }
+}
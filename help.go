@@ -0,0 +1,318 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// binding is one entry of the action registry: the keys a mode handles and
+// the action they run. Remappable bindings leave keys nil and take the key
+// their action has in the keymap, so the help always shows the configured key.
+type binding struct {
+    mode   string
+    keys   []string
+    action string
+    desc   string
+}
+
+// helpModes orders the groups in the help overlay.
+var helpModes = []string{
+    "Any view", "Global", "Table list", "Preview focus", "Editing", "Search", "Confirm",
+    "Prompts and filters", "Detail view", "JSON tree", "Hex viewer", "Schema tab",
+    "Referencing rows", "Query pane", "Query results", "Query history", "Command palette", "Help",
+}
+
+// bindings is the action registry behind key dispatch, the help overlay and
+// the key conflict checks of the config file. The views' Update functions
+// switch on the action actionFor resolves, so a key listed here is a key that
+// works. Modes that take text (editing, prompts, the palette and history)
+// switch on key types themselves; their entries only document them.
+var bindings = []binding{
+    {"Any view", []string{"ctrl+c"}, "quit", "quit (closes prompts, history and the palette instead)"},
+
+    {"Global", nil, "quit", "quit (asks first when staged changes are uncommitted)"},
+    {"Global", []string{"?"}, "help", "show this help"},
+    {"Global", []string{"ctrl+p"}, "palette", "command palette"},
+    {"Global", []string{"tab"}, "schema", "switch the right pane between data and schema"},
+    {"Global", []string{":"}, "query", "open the SQL query pane"},
+    {"Global", []string{"pgup"}, "prev-page", "previous page of rows"},
+    {"Global", []string{"pgdown"}, "next-page", "next page of rows"},
+    {"Global", []string{"T"}, "staging", "toggle staging mode"},
+    {"Global", []string{"C"}, "commit", "commit staged changes"},
+    {"Global", []string{"X"}, "rollback", "roll back staged changes"},
+    {"Global", []string{"u"}, "undo", "undo the last edit, delete or insert"},
+    {"Global", []string{"ctrl+r"}, "redo", "redo what was undone"},
+    {"Global", []string{"b"}, "back", "go back after a foreign key jump"},
+    {"Global", nil, "reload", "reload the table list"},
+    {"Global", []string{"I"}, "import", "import a CSV, TSV or NDJSON file"},
+    {"Global", []string{"E"}, "export", "export the table with its filters and sort"},
+
+    {"Table list", []string{"up", "k"}, "up", "previous table"},
+    {"Table list", []string{"down", "j"}, "down", "next table"},
+    {"Table list", []string{"right", "l"}, "right", "focus the preview"},
+    {"Table list", []string{"/"}, "search", "search tables"},
+    {"Table list", nil, "delete", "drop the table or view (asks first)"},
+
+    {"Preview focus", []string{"up", "k"}, "up", "previous row, across pages"},
+    {"Preview focus", []string{"down", "j"}, "down", "next row, across pages"},
+    {"Preview focus", []string{"left", "h"}, "left", "previous column (left of the first goes back to the list)"},
+    {"Preview focus", []string{"right", "l"}, "right", "next column"},
+    {"Preview focus", []string{"g"}, "first-page", "first page"},
+    {"Preview focus", []string{"G"}, "last-page", "last page"},
+    {"Preview focus", []string{"enter"}, "detail", "open the row in the detail view"},
+    {"Preview focus", nil, "edit", "edit the cell (toggles booleans)"},
+    {"Preview focus", []string{"e"}, "edit-external", "edit the cell in $EDITOR"},
+    {"Preview focus", nil, "copy", "copy the cell"},
+    {"Preview focus", nil, "insert", "insert a row (duplicates the selected one)"},
+    {"Preview focus", nil, "delete", "delete the row (asks first)"},
+    {"Preview focus", []string{"t"}, "restore", "restore the row from a trash table"},
+    {"Preview focus", []string{"s"}, "sort", "sort by the column: ascending, descending, off"},
+    {"Preview focus", []string{"f"}, "filter", "filter on the column"},
+    {"Preview focus", []string{"F"}, "unfilter", "remove the column's (or the latest) filter"},
+    {"Preview focus", []string{"p"}, "pin", "pin / unpin primary key columns"},
+    {"Preview focus", []string{"o"}, "follow", "follow the foreign key to its parent row"},
+    {"Preview focus", []string{"R"}, "refs", "list rows referencing this row"},
+    {"Preview focus", []string{"B"}, "hex", "hex view of the cell"},
+    {"Preview focus", []string{"J"}, "json", "add a json_extract column, or remove a computed one"},
+
+    {"Editing", []string{"enter", "ctrl+s"}, "", "save"},
+    {"Editing", []string{"alt+enter"}, "", "insert a newline"},
+    {"Editing", []string{"esc"}, "", "cancel"},
+    {"Editing", []string{"ctrl+left", "ctrl+right", "alt+b", "alt+f"}, "", "move by word"},
+    {"Editing", []string{"ctrl+w", "alt+backspace"}, "", "delete the word before the cursor"},
+    {"Editing", []string{"alt+d"}, "", "delete the word after the cursor"},
+    {"Editing", []string{"ctrl+k"}, "", "delete to the end of the line"},
+    {"Editing", []string{"home", "end", "ctrl+home", "ctrl+end"}, "", "start / end of line or text"},
+
+    {"Search", []string{"enter"}, "keep", "keep the search and return to the list"},
+    {"Search", []string{"esc"}, "clear", "clear the search"},
+    {"Search", []string{"up"}, "up", "previous table (typing narrows the list)"},
+    {"Search", []string{"down"}, "down", "next table"},
+    {"Search", []string{"right"}, "right", "keep the search and focus the preview"},
+
+    {"Confirm", []string{"y"}, "", "confirm the drop, delete or quit"},
+    {"Confirm", []string{"n", "esc"}, "", "cancel"},
+
+    {"Prompts and filters", []string{"enter"}, "", "apply"},
+    {"Prompts and filters", []string{"esc", "ctrl+c"}, "", "cancel"},
+    {"Prompts and filters", []string{"ctrl+u"}, "", "clear a status-line prompt"},
+
+    {"Detail view", []string{"up", "k"}, "up", "previous field"},
+    {"Detail view", []string{"down", "j"}, "down", "next field"},
+    {"Detail view", []string{"left", "h"}, "left", "previous record"},
+    {"Detail view", []string{"right", "l"}, "right", "next record"},
+    {"Detail view", []string{"pgup", "ctrl+u"}, "page-up", "scroll up"},
+    {"Detail view", []string{"pgdown", "ctrl+d"}, "page-down", "scroll down"},
+    {"Detail view", nil, "edit", "edit the field"},
+    {"Detail view", []string{"e"}, "edit-external", "edit the field in $EDITOR"},
+    {"Detail view", nil, "copy", "copy the field"},
+    {"Detail view", []string{"J"}, "json", "open the field as a JSON tree"},
+    {"Detail view", []string{"B"}, "hex", "hex view of the field"},
+    {"Detail view", []string{"esc", "enter", "q"}, "back", "back"},
+
+    {"JSON tree", []string{"up", "k"}, "up", "previous node"},
+    {"JSON tree", []string{"down", "j"}, "down", "next node"},
+    {"JSON tree", []string{"pgup", "ctrl+u"}, "page-up", "half a page up"},
+    {"JSON tree", []string{"pgdown", "ctrl+d"}, "page-down", "half a page down"},
+    {"JSON tree", []string{"g", "home"}, "top", "first node"},
+    {"JSON tree", []string{"G", "end"}, "bottom", "last node"},
+    {"JSON tree", []string{"enter", " "}, "toggle", "fold / unfold"},
+    {"JSON tree", []string{"left", "h"}, "parent", "fold, or go to the parent"},
+    {"JSON tree", []string{"right", "l"}, "child", "unfold, or step into the first child"},
+    {"JSON tree", []string{"y"}, "copy", "copy the value"},
+    {"JSON tree", []string{"p"}, "copy-path", "copy its path"},
+    {"JSON tree", []string{"v"}, "column", "add the path as a computed column"},
+    {"JSON tree", []string{"esc", "q"}, "back", "back"},
+
+    {"Hex viewer", []string{"up", "k"}, "up", "scroll up"},
+    {"Hex viewer", []string{"down", "j"}, "down", "scroll down"},
+    {"Hex viewer", []string{"pgup", "ctrl+u"}, "page-up", "page up"},
+    {"Hex viewer", []string{"pgdown", "ctrl+d"}, "page-down", "page down"},
+    {"Hex viewer", []string{"g", "home"}, "top", "start"},
+    {"Hex viewer", []string{"G", "end"}, "bottom", "end"},
+    {"Hex viewer", []string{"w"}, "save", "save the bytes to a file"},
+    {"Hex viewer", []string{"r"}, "load", "load a file into the cell"},
+    {"Hex viewer", []string{"esc", "q"}, "back", "back"},
+
+    {"Schema tab", []string{"up", "k"}, "up", "scroll up"},
+    {"Schema tab", []string{"down", "j"}, "down", "scroll down"},
+    {"Schema tab", []string{"pgup", "ctrl+u"}, "page-up", "half a page up"},
+    {"Schema tab", []string{"pgdown", "ctrl+d"}, "page-down", "half a page down"},
+    {"Schema tab", []string{"g"}, "top", "top"},
+    {"Schema tab", []string{"G"}, "bottom", "bottom"},
+    {"Schema tab", []string{"tab"}, "schema", "back to the data"},
+    {"Schema tab", []string{"left", "h", "esc"}, "back", "back to the table list"},
+    {"Schema tab", nil, "quit", "quit"},
+
+    {"Referencing rows", []string{"up", "k"}, "up", "previous table"},
+    {"Referencing rows", []string{"down", "j"}, "down", "next table"},
+    {"Referencing rows", []string{"enter"}, "open", "open the referencing rows"},
+    {"Referencing rows", []string{"esc", "q", "R"}, "back", "back"},
+
+    {"Query pane", []string{"f5", "ctrl+j"}, "run", "run the statement (Ctrl+Enter)"},
+    {"Query pane", []string{"tab"}, "focus", "switch between editor and results"},
+    {"Query pane", []string{"ctrl+r"}, "history", "query history"},
+    {"Query pane", []string{"esc"}, "close", "close"},
+
+    {"Query results", []string{"up", "k"}, "up", "previous row"},
+    {"Query results", []string{"down", "j"}, "down", "next row"},
+    {"Query results", []string{"left", "h"}, "left", "previous column"},
+    {"Query results", []string{"right", "l"}, "right", "next column"},
+    {"Query results", nil, "copy", "copy the cell"},
+    {"Query results", []string{"E"}, "export", "export the result"},
+
+    {"Query history", []string{"up", "down", "ctrl+p", "ctrl+n"}, "", "move (typing narrows the list)"},
+    {"Query history", []string{"enter"}, "", "recall the query"},
+    {"Query history", []string{"esc", "ctrl+c"}, "", "close"},
+
+    {"Command palette", []string{"up", "down", "ctrl+p", "ctrl+n"}, "", "move (typing narrows the list, recent commands first)"},
+    {"Command palette", []string{"enter"}, "", "run the command"},
+    {"Command palette", []string{"esc", "ctrl+c"}, "", "close"},
+
+    {"Help", []string{"up", "k"}, "up", "scroll up"},
+    {"Help", []string{"down", "j"}, "down", "scroll down"},
+    {"Help", []string{"pgup", "ctrl+u"}, "page-up", "half a page up"},
+    {"Help", []string{"pgdown", "ctrl+d", " "}, "page-down", "half a page down"},
+    {"Help", []string{"g", "home"}, "top", "top"},
+    {"Help", []string{"G", "end"}, "bottom", "bottom"},
+    {"Help", []string{"esc", "q", "?"}, "close", "close"},
+}
+
+// actionFor resolves a key press to the action bound to it in the first of
+// modes that binds it, honouring remapped keys: the default key of a
+// remapped action resolves to nothing. It returns "" for unbound keys.
+func (m model) actionFor(key string, modes ...string) string {
+    for _, mode := range modes {
+        for _, b := range bindings {
+            if b.mode != mode {
+                continue
+            }
+            if b.keys == nil && m.keys.key(b.action) == key || containsString(b.keys, key) {
+                return b.action
+            }
+        }
+    }
+    return ""
+}
+
+// fixedKeys are the keys of the registry's non-remappable bindings in the
+// modes where remapped actions are looked up; remaps can't take them.
+func fixedKeys() []string {
+    remapped := map[string]bool{}
+    for _, b := range bindings {
+        if b.keys == nil { remapped[b.mode] = true }
+    }
+    var out []string
+    for _, b := range bindings {
+        if remapped[b.mode] || b.mode == "Any view" {
+            out = append(out, b.keys...)
+        }
+    }
+    return out
+}
+
+// helpKeyName is how a key is written in the help overlay.
+func helpKeyName(k string) string {
+    switch k {
+    case "up":
+        return "↑"
+    case "down":
+        return "↓"
+    case "left":
+        return "←"
+    case "right":
+        return "→"
+    case " ":
+        return "Space"
+    case "pgup":
+        return "PgUp"
+    case "pgdown":
+        return "PgDn"
+    case "enter", "esc", "tab", "home", "end":
+        return strings.ToUpper(k[:1]) + k[1:]
+    }
+    if mod, rest, ok := strings.Cut(k, "+"); ok && len(k) > 2 {
+        return strings.ToUpper(mod[:1]) + mod[1:] + "+" + helpKeyName(rest)
+    }
+    if len(k) > 1 {
+        return strings.ToUpper(k[:1]) + k[1:]
+    }
+    return k
+}
+
+// helpLines renders the registry grouped by mode, with remapped keys.
+func (m model) helpLines(width int) []string {
+    const keyWidth = 24
+    var out []string
+    for _, mode := range helpModes {
+        out = append(out, styleHeader.Render(mode))
+        for _, b := range bindings {
+            if b.mode != mode {
+                continue
+            }
+            keys := b.keys
+            if b.keys == nil {
+                keys = []string{m.keys.key(b.action)}
+            }
+            names := make([]string, len(keys))
+            for i, k := range keys {
+                names[i] = helpKeyName(k)
+            }
+            line := "  " + styleColSelect.Render(padRightANSI(strings.Join(names, " "), keyWidth)) + " " + b.desc
+            out = append(out, truncateANSI(line, max(1, width)))
+        }
+        out = append(out, "")
+    }
+    return out[:len(out)-1]
+}
+
+// helpHeight is the number of help lines that fit on screen.
+func (m model) helpHeight() int {
+    if m.height <= 0 {
+        return 20
+    }
+    return max(1, m.height-3)
+}
+
+// updateHelp handles keys while the help overlay is open.
+func (m model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    h := m.helpHeight()
+    maxScroll := max(0, len(m.helpLines(m.width))-h)
+    switch m.actionFor(msg.String(), "Help", "Any view") {
+    case "quit":
+        return m.quit()
+    case "close":
+        m.helpActive = false
+    case "up":
+        m.helpScroll--
+    case "down":
+        m.helpScroll++
+    case "page-up":
+        m.helpScroll -= max(1, h/2)
+    case "page-down":
+        m.helpScroll += max(1, h/2)
+    case "top":
+        m.helpScroll = 0
+    case "bottom":
+        m.helpScroll = maxScroll
+    }
+    m.helpScroll = max(0, min(m.helpScroll, maxScroll))
+    return m, nil
+}
+
+// renderHelp draws the help overlay over the whole screen.
+func (m model) renderHelp() string {
+    width := m.width
+    if width <= 0 { width = 100 }
+    lines := m.helpLines(width)
+    h := m.helpHeight()
+    var b strings.Builder
+    title := fmt.Sprintf("Keys (%d-%d of %d, j/k scroll, Esc close)", m.helpScroll+1, min(len(lines), m.helpScroll+h), len(lines))
+    b.WriteString(styleHeader.Render(title) + "\n")
+    for i := m.helpScroll; i < len(lines) && i < m.helpScroll+h; i++ {
+        b.WriteString(lines[i] + "\n")
+    }
+    return b.String()
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestBindingsUnambiguous(t *testing.T) {
+    m := model{keys: defaultKeymap()}
+    seen := map[string]string{}
+    for _, b := range bindings {
+        if !containsString(helpModes, b.mode) {
+            t.Errorf("mode %q is missing from helpModes", b.mode)
+        }
+        keys := b.keys
+        if keys == nil {
+            if m.keys.key(b.action) == "" {
+                t.Errorf("%s: remappable binding %q is not a keymap action", b.mode, b.action)
+            }
+            keys = []string{m.keys.key(b.action)}
+        }
+        for _, k := range keys {
+            id := b.mode + " " + k
+            if other, dup := seen[id]; dup && other != b.action {
+                t.Errorf("%s: %q runs both %s and %s", b.mode, k, other, b.action)
+            }
+            seen[id] = b.action
+        }
+    }
+}
+
+func TestActionFor(t *testing.T) {
+    k := defaultKeymap()
+    k["quit"], k["copy"] = "Q", "ctrl+y"
+    m := model{keys: k}
+    tests := []struct {
+        key   string
+        modes []string
+        want  string
+    }{
+        {"Q", []string{"Table list", "Global"}, "quit"},
+        {"q", []string{"Table list", "Global"}, ""},
+        {"ctrl+y", []string{"Preview focus", "Global"}, "copy"},
+        {"y", []string{"Preview focus", "Global"}, ""},
+        {"x", []string{"Table list", "Global"}, "delete"},
+        {"l", []string{"Table list", "Global"}, "right"},
+        {"tab", []string{"Schema tab", "Any view"}, "schema"},
+        {"ctrl+c", []string{"Help", "Any view"}, "quit"},
+        {" ", []string{"Help"}, "page-down"},
+        {"home", []string{"JSON tree"}, "top"},
+        // keys of other modes don't leak in
+        {"?", []string{"Detail view", "Any view"}, ""},
+        {"t", []string{"Table list", "Global"}, ""},
+    }
+    for _, tt := range tests {
+        if got := m.actionFor(tt.key, tt.modes...); got != tt.want {
+            t.Errorf("actionFor(%q, %q) = %q, want %q", tt.key, tt.modes, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "os"
+    "strings"
+    "unicode/utf8"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// blobMagic maps leading bytes to a file type for the hex viewer title.
+var blobMagic = []struct {
+    prefix []byte
+    name   string
+}{
+    {[]byte("\x89PNG\r\n\x1a\n"), "PNG image"},
+    {[]byte("\xff\xd8\xff"), "JPEG image"},
+    {[]byte("GIF87a"), "GIF image"},
+    {[]byte("GIF89a"), "GIF image"},
+    {[]byte("%PDF-"), "PDF document"},
+    {[]byte("\x1f\x8b"), "gzip"},
+    {[]byte("PK\x03\x04"), "zip archive"},
+    {[]byte("BZh"), "bzip2"},
+    {[]byte("\x28\xb5\x2f\xfd"), "zstd"},
+    {[]byte("\xfd7zXZ\x00"), "xz"},
+    {[]byte("SQLite format 3\x00"), "SQLite database"},
+    {[]byte("\x7fELF"), "ELF binary"},
+    {[]byte("OggS"), "Ogg"},
+    {[]byte("ID3"), "MP3 audio"},
+    {[]byte("\x00asm"), "WebAssembly"},
+}
+
+// detectBlobType names the content of b from its magic number, falling back
+// to text/JSON checks and a protobuf wire-format heuristic.
+func detectBlobType(b []byte) string {
+    if len(b) == 0 {
+        return "empty"
+    }
+    for _, mg := range blobMagic {
+        if bytes.HasPrefix(b, mg.prefix) {
+            return mg.name
+        }
+    }
+    if len(b) >= 12 && string(b[:4]) == "RIFF" {
+        switch string(b[8:12]) {
+        case "WEBP":
+            return "WebP image"
+        case "WAVE":
+            return "WAV audio"
+        }
+    }
+    if utf8.Valid(b) && isMostlyPrintable(string(b)) {
+        if looksLikeJSON(string(b)) {
+            return "JSON text"
+        }
+        return "UTF-8 text"
+    }
+    if looksLikeProtobuf(b) {
+        return "protobuf (probable)"
+    }
+    return "binary"
+}
+
+// looksLikeProtobuf reports whether b parses cleanly as a sequence of
+// protobuf wire-format fields. It's a heuristic: many short inputs pass.
+func looksLikeProtobuf(b []byte) bool {
+    fields := 0
+    for len(b) > 0 {
+        key, n := readVarint(b)
+        if n == 0 {
+            return false
+        }
+        b = b[n:]
+        if key>>3 == 0 {
+            return false
+        }
+        switch key & 7 {
+        case 0: // varint
+            _, n = readVarint(b)
+            if n == 0 {
+                return false
+            }
+            b = b[n:]
+        case 1: // 64-bit
+            if len(b) < 8 {
+                return false
+            }
+            b = b[8:]
+        case 2: // length-delimited
+            l, n := readVarint(b)
+            if n == 0 || uint64(len(b)-n) < l {
+                return false
+            }
+            b = b[n+int(l):]
+        case 5: // 32-bit
+            if len(b) < 4 {
+                return false
+            }
+            b = b[4:]
+        default:
+            return false
+        }
+        fields++
+    }
+    return fields > 0
+}
+
+func readVarint(b []byte) (uint64, int) {
+    var v uint64
+    for i := 0; i < len(b) && i < 10; i++ {
+        v |= uint64(b[i]&0x7f) << (7 * i)
+        if b[i] < 0x80 {
+            return v, i + 1
+        }
+    }
+    return 0, 0
+}
+
+// hexBytesPerLine fits the dump to width: 16 bytes when there is room, else 8.
+func hexBytesPerLine(width int) int {
+    if width >= 78 {
+        return 16
+    }
+    return 8
+}
+
+// hexDumpLine formats the bytes at offset off like hexdump -C:
+// offset, hex bytes in groups of eight, and the printable ASCII.
+func hexDumpLine(b []byte, off, per int) string {
+    end := min(off+per, len(b))
+    var hx, asc strings.Builder
+    for i := off; i < off+per; i++ {
+        if i > off && (i-off)%8 == 0 {
+            hx.WriteString(" ")
+        }
+        if i < end {
+            fmt.Fprintf(&hx, "%02x ", b[i])
+            if b[i] >= 32 && b[i] < 127 {
+                asc.WriteByte(b[i])
+            } else {
+                asc.WriteByte('.')
+            }
+        } else {
+            hx.WriteString("   ")
+        }
+    }
+    return fmt.Sprintf("%s  %s |%s|", styleDim.Render(fmt.Sprintf("%08x", off)), hx.String(), asc.String())
+}
+
+// cellBytes reads the selected cell as raw bytes; text is shown as its UTF-8
+// encoding. Numbers have no byte form.
+func (m model) cellBytes() ([]byte, error) {
+    loc, ok := m.rowLocator(m.selRow)
+    if !ok {
+        return nil, fmt.Errorf("row has no key")
+    }
+    v, err := readCell(m.conn(), m.previewTable, loc, m.previewColumns[m.selCol])
+    if err != nil {
+        return nil, err
+    }
+    switch t := v.(type) {
+    case nil:
+        return nil, nil
+    case []byte:
+        return t, nil
+    case string:
+        return []byte(t), nil
+    }
+    return nil, fmt.Errorf("%s holds a %T, not a blob", m.previewColumns[m.selCol], v)
+}
+
+// openHexView shows the selected cell in the hex viewer.
+func (m *model) openHexView() {
+    if m.selRow < 0 || m.selRow >= len(m.preview) || m.selCol < 0 || m.selCol >= len(m.previewColumns) {
+        return
+    }
+    b, err := m.cellBytes()
+    if err != nil {
+        m.status = fmt.Sprintf("hex view error: %v", err)
+        return
+    }
+    m.hexActive = true
+    m.hexData = b
+    m.hexScroll = 0
+}
+
+// updateHexView handles keys in the hex viewer.
+func (m model) updateHexView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    per := hexBytesPerLine(m.gridWidth())
+    lines := (len(m.hexData) + per - 1) / per
+    h := max(1, m.detailHeight()-1)
+    switch m.actionFor(msg.String(), "Hex viewer", "Any view") {
+    case "quit":
+        return m.quit()
+    case "back":
+        m.hexActive = false
+        m.hexData = nil
+    case "down":
+        m.hexScroll++
+    case "up":
+        m.hexScroll--
+    case "page-down":
+        m.hexScroll += h
+    case "page-up":
+        m.hexScroll -= h
+    case "top":
+        m.hexScroll = 0
+    case "bottom":
+        m.hexScroll = lines - h
+    case "save":
+        m.openPrompt("blob-save", "save blob to file", m.previewColumns[m.selCol]+blobExtension(m.hexData))
+    case "load":
+        if !m.denyWrite("loading blobs") {
+            m.openPrompt("blob-load", "load file into "+m.previewColumns[m.selCol], "")
+        }
+    }
+    m.hexScroll = max(0, min(m.hexScroll, lines-h))
+    return m, nil
+}
+
+// blobExtension suggests a file extension for saving b.
+func blobExtension(b []byte) string {
+    switch detectBlobType(b) {
+    case "PNG image":
+        return ".png"
+    case "JPEG image":
+        return ".jpg"
+    case "GIF image":
+        return ".gif"
+    case "PDF document":
+        return ".pdf"
+    case "gzip":
+        return ".gz"
+    case "zip archive":
+        return ".zip"
+    case "JSON text":
+        return ".json"
+    case "UTF-8 text":
+        return ".txt"
+    }
+    return ".bin"
+}
+
+// renderHexView draws the hex dump of the selected cell into b.
+func (m model) renderHexView(b *strings.Builder, width int) {
+    col := m.previewColumns[m.selCol]
+    title := fmt.Sprintf("Hex: %s.%s (%s) %d bytes, %s", m.previewTable, col, m.pagePosition(), len(m.hexData), detectBlobType(m.hexData))
+    b.WriteString(styleHeader.Render(title) + "\n")
+    b.WriteString(styleDim.Render("j/k scroll, PgUp/PgDn page, g/G start/end, w save to file, r load file, Esc back") + "\n")
+    per := hexBytesPerLine(width)
+    h := max(1, m.detailHeight()-1)
+    for i := 0; i < h; i++ {
+        off := (m.hexScroll + i) * per
+        if off >= len(m.hexData) {
+            break
+        }
+        b.WriteString("  " + truncateANSI(hexDumpLine(m.hexData, off, per), max(1, width-2)) + "\n")
+    }
+}
+
+// saveBlob writes the viewed bytes to path.
+func (m *model) saveBlob(path string) error {
+    return os.WriteFile(path, m.hexData, 0o644)
+}
+
+// loadBlob replaces the selected cell with the contents of path, bound as a
+// BLOB parameter.
+func (m *model) loadBlob(path string) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return err
+    }
+    if data == nil {
+        data = []byte{}
+    }
+    if err := m.updateSelectedCell(data); err != nil {
+        return err
+    }
+    m.hexData = data
+    m.hexScroll = 0
+    return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+    "bufio"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+)
+
+// appName names the per-user config directory (e.g. ~/.config/tui-sql).
+const appName = "tui-sql"
+
+// historyEntry is one executed ad-hoc statement, stored as a JSON line.
+type historyEntry struct {
+    Time       time.Time `json:"time"`
+    DB         string    `json:"db"`
+    SQL        string    `json:"sql"`
+    DurationMS float64   `json:"duration_ms"`
+    Rows       int64     `json:"rows"`
+    Error      string    `json:"error,omitempty"`
+}
+
+// configDir returns the per-user config directory for this tool.
+func configDir() (string, error) {
+    base, err := os.UserConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(base, appName), nil
+}
+
+func historyPath() (string, error) {
+    dir, err := configDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// newHistoryEntry builds a history record for a finished query.
+func newHistoryEntry(dbPath string, res queryResult) historyEntry {
+    e := historyEntry{
+        Time:       time.Now(),
+        DB:         dbPath,
+        SQL:        res.SQL,
+        DurationMS: float64(res.Duration.Microseconds()) / 1000,
+        Rows:       res.rowCount(),
+    }
+    if res.Err != nil {
+        e.Error = res.Err.Error()
+    }
+    return e
+}
+
+// appendHistory adds e to the history file, creating it if needed.
+func appendHistory(e historyEntry) error {
+    path, err := historyPath()
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    b, err := json.Marshal(e)
+    if err != nil {
+        return err
+    }
+    _, err = f.Write(append(b, '\n'))
+    return err
+}
+
+// loadHistory reads the history file, newest entry first. A missing file is
+// an empty history; malformed lines are skipped.
+func loadHistory() ([]historyEntry, error) {
+    path, err := historyPath()
+    if err != nil {
+        return nil, err
+    }
+    f, err := os.Open(path)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    var out []historyEntry
+    sc := bufio.NewScanner(f)
+    sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+    for sc.Scan() {
+        var e historyEntry
+        if err := json.Unmarshal(sc.Bytes(), &e); err != nil || e.SQL == "" {
+            continue
+        }
+        out = append(out, e)
+    }
+    reverseRows(out)
+    return out, sc.Err()
+}
+
+// filterHistory returns the indexes of entries matching query, best match first.
+// Entries with equal scores keep their newest-first order.
+func filterHistory(entries []historyEntry, query string) []int {
+    type scored struct{ idx, score int }
+    var hits []scored
+    for i, e := range entries {
+        if score, ok := fuzzyMatch(query, e.SQL); ok {
+            hits = append(hits, scored{i, score})
+        }
+    }
+    sort.SliceStable(hits, func(a, b int) bool { return hits[a].score > hits[b].score })
+    out := make([]int, len(hits))
+    for i, h := range hits { out[i] = h.idx }
+    return out
+}
+
+// oneLine collapses whitespace so multi-line SQL fits on a single list row.
+func oneLine(s string) string {
+    return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+// importConflicts are the ways an import can treat rows that violate a
+// constraint: stop and roll everything back, skip the row, or replace the
+// existing row.
+var importConflicts = []string{"abort", "skip", "replace"}
+
+// importData is a file read into memory: its header and one value per column
+// for each record (nil for NULL).
+type importData struct {
+    Columns []string
+    Rows    [][]any
+}
+
+// importStats reports what an import did.
+type importStats struct {
+    Table    string
+    Created  bool
+    Inserted int
+    Skipped  int
+    Failed   int
+    Ignored  []string // file columns with no matching table column
+    FirstErr error    // first row error, when rows failed
+}
+
+func (s importStats) summary() string {
+    verb := "imported into"
+    if s.Created { verb = "created and imported into" }
+    out := fmt.Sprintf("%s %s: %d inserted, %d skipped, %d failed", verb, s.Table, s.Inserted, s.Skipped, s.Failed)
+    if len(s.Ignored) > 0 {
+        out += " (ignored columns: " + strings.Join(s.Ignored, ", ") + ")"
+    }
+    if s.FirstErr != nil {
+        out += fmt.Sprintf("; first error: %v", s.FirstErr)
+    }
+    return out
+}
+
+// readImportFile parses a CSV/TSV file with a header row, or NDJSON with one
+// object per line. Empty CSV fields are read as NULL.
+func readImportFile(path string) (importData, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return importData{}, err
+    }
+    defer f.Close()
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".csv":
+        return readCSV(f, ',')
+    case ".tsv", ".tab":
+        return readCSV(f, '\t')
+    case ".ndjson", ".jsonl", ".json":
+        return readNDJSON(f)
+    }
+    return importData{}, fmt.Errorf("unknown import format for %q (use .csv, .tsv, .ndjson, .jsonl or .json)", path)
+}
+
+func readCSV(r io.Reader, comma rune) (importData, error) {
+    cr := csv.NewReader(r)
+    cr.Comma = comma
+    cr.FieldsPerRecord = -1
+    header, err := cr.Read()
+    if err == io.EOF {
+        return importData{}, fmt.Errorf("file is empty")
+    }
+    if err != nil {
+        return importData{}, err
+    }
+    if len(header) > 0 {
+        header[0] = strings.TrimPrefix(header[0], "\ufeff")
+    }
+    d := importData{Columns: header}
+    for {
+        rec, err := cr.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return d, err
+        }
+        row := make([]any, len(header))
+        for i := range header {
+            if i < len(rec) && rec[i] != "" {
+                row[i] = rec[i]
+            }
+        }
+        d.Rows = append(d.Rows, row)
+    }
+    return d, nil
+}
+
+// readNDJSON reads one JSON object per line, or a single JSON array of
+// objects (as written by the JSON export). Columns are the keys in order of
+// first appearance; nested objects and arrays are stored as JSON text.
+func readNDJSON(r io.Reader) (importData, error) {
+    var d importData
+    var raws []json.RawMessage
+    dec := json.NewDecoder(r)
+    for {
+        var raw json.RawMessage
+        if err := dec.Decode(&raw); err == io.EOF {
+            break
+        } else if err != nil {
+            return d, fmt.Errorf("record %d: %w", len(raws)+1, err)
+        }
+        if len(raws) == 0 && strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+            if err := json.Unmarshal(raw, &raws); err != nil {
+                return d, err
+            }
+            continue
+        }
+        raws = append(raws, raw)
+    }
+    index := map[string]int{}
+    objs := make([]map[string]any, len(raws))
+    for n, raw := range raws {
+        keys, err := objectKeys(raw)
+        if err != nil {
+            return d, fmt.Errorf("record %d: %w", n+1, err)
+        }
+        vd := json.NewDecoder(strings.NewReader(string(raw)))
+        vd.UseNumber()
+        if err := vd.Decode(&objs[n]); err != nil {
+            return d, fmt.Errorf("record %d: %w", n+1, err)
+        }
+        for _, k := range keys {
+            if _, ok := index[k]; !ok {
+                index[k] = len(d.Columns)
+                d.Columns = append(d.Columns, k)
+            }
+        }
+    }
+    if len(d.Columns) == 0 {
+        return d, fmt.Errorf("file has no records")
+    }
+    for _, obj := range objs {
+        row := make([]any, len(d.Columns))
+        for k, v := range obj {
+            row[index[k]] = importJSONValue(v)
+        }
+        d.Rows = append(d.Rows, row)
+    }
+    return d, nil
+}
+
+// objectKeys returns the keys of a JSON object in the order they appear.
+func objectKeys(raw json.RawMessage) ([]string, error) {
+    dec := json.NewDecoder(strings.NewReader(string(raw)))
+    if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+        return nil, fmt.Errorf("expected a JSON object")
+    }
+    var keys []string
+    for dec.More() {
+        t, err := dec.Token()
+        if err != nil {
+            return nil, err
+        }
+        keys = append(keys, t.(string))
+        var skip json.RawMessage
+        if err := dec.Decode(&skip); err != nil {
+            return nil, err
+        }
+    }
+    return keys, nil
+}
+
+// importJSONValue converts a decoded JSON value into something SQLite can bind.
+func importJSONValue(v any) any {
+    switch t := v.(type) {
+    case json.Number:
+        if n, err := t.Int64(); err == nil {
+            return n
+        }
+        if f, err := t.Float64(); err == nil {
+            return f
+        }
+        return t.String()
+    case bool:
+        if t { return int64(1) }
+        return int64(0)
+    case map[string]any, []any:
+        b, _ := json.Marshal(t)
+        return string(b)
+    }
+    return v
+}
+
+// inferColumnType picks INTEGER, REAL or TEXT for a new table column from
+// the values in column i. NULLs don't count; an all-NULL column is TEXT.
+// Text only counts as a number when it reads back the same, so values like
+// "007" or "+5" (zip codes, phone numbers) stay TEXT.
+func inferColumnType(rows [][]any, i int) string {
+    typ := ""
+    for _, r := range rows {
+        switch v := r[i].(type) {
+        case nil:
+            continue
+        case int64:
+            if typ == "" { typ = "INTEGER" }
+        case float64:
+            if typ != "TEXT" { typ = "REAL" }
+        case string:
+            if n, err := strconv.ParseInt(v, 10, 64); err == nil && strconv.FormatInt(n, 10) == v {
+                if typ == "" { typ = "INTEGER" }
+            } else if f, err := strconv.ParseFloat(v, 64); err == nil && (strconv.FormatFloat(f, 'f', -1, 64) == v || strconv.FormatFloat(f, 'g', -1, 64) == v) {
+                if typ != "TEXT" { typ = "REAL" }
+            } else {
+                return "TEXT"
+            }
+        default:
+            return "TEXT"
+        }
+    }
+    if typ == "" {
+        return "TEXT"
+    }
+    return typ
+}
+
+// importFile loads path into table, creating the table when it doesn't exist.
+// All rows go in one transaction (a savepoint inside the staging transaction
+// when staging is on). With conflict "abort" the first failing row rolls the
+// whole import back; with "skip" or "replace" failing rows are counted and
+// the rest are kept.
+func (m *model) importFile(path, table, conflict string) (importStats, error) {
+    st := importStats{Table: table}
+    verb := map[string]string{"abort": "INSERT", "skip": "INSERT OR IGNORE", "replace": "INSERT OR REPLACE"}[conflict]
+    if verb == "" {
+        return st, fmt.Errorf("unknown conflict mode %q (want %s)", conflict, strings.Join(importConflicts, ", "))
+    }
+    data, err := readImportFile(path)
+    if err != nil {
+        return st, err
+    }
+    err = m.atomic("import", func(conn dbConn) error {
+        return m.importRows(conn, data, verb, conflict, &st)
+    })
+    if err != nil {
+        st.Inserted, st.Skipped = 0, 0
+        return st, err
+    }
+    m.noteChange()
+    return st, nil
+}
+
+// importRows creates or matches the target table and inserts the rows.
+func (m *model) importRows(conn dbConn, data importData, verb, conflict string, st *importStats) error {
+    cols, err := getTableInfo(conn, st.Table)
+    if err != nil {
+        return err
+    }
+    if len(cols) == 0 {
+        defs := make([]string, len(data.Columns))
+        for i, c := range data.Columns {
+            defs[i] = quoteIdent(c) + " " + inferColumnType(data.Rows, i)
+        }
+        if _, err := conn.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(st.Table), strings.Join(defs, ", "))); err != nil {
+            return err
+        }
+        st.Created = true
+        for _, c := range data.Columns {
+            cols = append(cols, colInfo{Name: c})
+        }
+    }
+    // map file columns onto table columns by name, ignoring case
+    var names []string
+    var idx []int
+    for i, c := range data.Columns {
+        found := false
+        for _, tc := range cols {
+            if strings.EqualFold(tc.Name, c) {
+                names = append(names, tc.Name)
+                idx = append(idx, i)
+                found = true
+                break
+            }
+        }
+        if !found {
+            st.Ignored = append(st.Ignored, c)
+        }
+    }
+    if len(names) == 0 {
+        return fmt.Errorf("no columns in the file match table %s", st.Table)
+    }
+    q := fmt.Sprintf("%s INTO %s (%s) VALUES (%s)", verb, quoteIdent(st.Table), quoteIdentList(names), placeholders(len(names)))
+    args := make([]any, len(names))
+    for ri, row := range data.Rows {
+        for j, i := range idx {
+            args[j] = row[i]
+        }
+        res, err := conn.Exec(q, args...)
+        if err != nil {
+            if conflict == "abort" {
+                return fmt.Errorf("row %d: %w", ri+1, err)
+            }
+            st.Failed++
+            if st.FirstErr == nil {
+                st.FirstErr = fmt.Errorf("row %d: %w", ri+1, err)
+            }
+            continue
+        }
+        if n, _ := res.RowsAffected(); n == 0 {
+            st.Skipped++
+        } else {
+            st.Inserted++
+        }
+    }
+    return nil
+}
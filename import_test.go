@@ -0,0 +1,58 @@
+package main
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestInferColumnType(t *testing.T) {
+    tests := []struct {
+        name string
+        vals []any
+        want string
+    }{
+        {"integers", []any{"1", "-20", nil, "300"}, "INTEGER"},
+        {"reals", []any{"1.5", "2", "-0.25"}, "REAL"},
+        {"exponent", []any{"1e+06"}, "REAL"},
+        {"json numbers", []any{int64(1), float64(2.5)}, "REAL"},
+        {"leading zeros", []any{"12", "007"}, "TEXT"},
+        {"plus sign", []any{"+5"}, "TEXT"},
+        {"padded zip", []any{"0012"}, "TEXT"},
+        {"trailing zero", []any{"1.50"}, "TEXT"},
+        {"words", []any{"1", "two"}, "TEXT"},
+        {"all null", []any{nil, nil}, "TEXT"},
+        {"bool", []any{true}, "TEXT"},
+    }
+    for _, tt := range tests {
+        rows := make([][]any, len(tt.vals))
+        for i, v := range tt.vals {
+            rows[i] = []any{v}
+        }
+        if got := inferColumnType(rows, 0); got != tt.want {
+            t.Errorf("%s: inferColumnType(%v) = %s, want %s", tt.name, tt.vals, got, tt.want)
+        }
+    }
+}
+
+func TestReadCSV(t *testing.T) {
+    in := "\ufeffid,name,note\n1,ann,\n2,\"b, c\"\n3,dan,\"multi\nline\"\n"
+    d, err := readCSV(strings.NewReader(in), ',')
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := []string{"id", "name", "note"}; !reflect.DeepEqual(d.Columns, want) {
+        t.Errorf("columns = %q, want %q", d.Columns, want)
+    }
+    want := [][]any{
+        {"1", "ann", nil},
+        {"2", "b, c", nil},
+        {"3", "dan", "multi\nline"},
+    }
+    if !reflect.DeepEqual(d.Rows, want) {
+        t.Errorf("rows = %q, want %q", d.Rows, want)
+    }
+    if _, err := readCSV(strings.NewReader(""), ','); err == nil {
+        t.Error("empty file: want an error")
+    }
+}
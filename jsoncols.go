@@ -0,0 +1,101 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+)
+
+// jsonColumn is a virtual preview column showing json_extract(Col, Path).
+type jsonColumn struct {
+    Col  string
+    Path string
+}
+
+// jsonColumnName is the header of a virtual JSON column, e.g. "data->$.user.id".
+func jsonColumnName(col, path string) string { return col + "->" + path }
+
+// jsonSelectExprs returns the select-list entries for the virtual JSON columns.
+func (m *model) jsonSelectExprs() []string {
+    out := make([]string, len(m.jsonCols))
+    for i, jc := range m.jsonCols {
+        out[i] = fmt.Sprintf("json_extract(%s, %s) AS %s", quoteIdent(jc.Col), sqlString(jc.Path), quoteIdent(jsonColumnName(jc.Col, jc.Path)))
+    }
+    return out
+}
+
+// isJSONColumn reports whether name is one of the virtual JSON columns.
+func (m model) isJSONColumn(name string) bool {
+    for _, jc := range m.jsonCols {
+        if jsonColumnName(jc.Col, jc.Path) == name {
+            return true
+        }
+    }
+    return false
+}
+
+// tableColumns returns the preview columns without the virtual JSON ones,
+// i.e. the columns the table itself has.
+func (m model) tableColumns() []string {
+    out := make([]string, 0, len(m.previewColumns))
+    for _, c := range m.previewColumns {
+        if !m.isJSONColumn(c) {
+            out = append(out, c)
+        }
+    }
+    return out
+}
+
+// addJSONColumn adds a virtual column extracting path from col. The path is
+// checked by SQLite first so a typo doesn't break the preview query.
+func (m *model) addJSONColumn(col, path string) error {
+    path = strings.TrimSpace(path)
+    if !strings.HasPrefix(path, "$") {
+        return fmt.Errorf("path must start with $, e.g. $.name or $.items[0]")
+    }
+    if m.isJSONColumn(col) {
+        return fmt.Errorf("%s is already a JSON column", col)
+    }
+    if m.isJSONColumn(jsonColumnName(col, path)) {
+        return fmt.Errorf("%s is already shown", jsonColumnName(col, path))
+    }
+    var v any
+    if err := m.conn().QueryRow("SELECT json_extract('{}', ?)", path).Scan(&v); err != nil {
+        return err
+    }
+    m.jsonCols = append(m.jsonCols, jsonColumn{Col: col, Path: path})
+    m.refreshPreview()
+    return nil
+}
+
+// removeJSONColumn drops the virtual column called name.
+func (m *model) removeJSONColumn(name string) bool {
+    for i, jc := range m.jsonCols {
+        if jsonColumnName(jc.Col, jc.Path) == name {
+            m.jsonCols = append(m.jsonCols[:i], m.jsonCols[i+1:]...)
+            m.refreshPreview()
+            return true
+        }
+    }
+    return false
+}
+
+// wantsJSON reports whether edits to col must be valid JSON: the column is
+// declared JSON, or the cell currently holds a JSON object or array.
+func (m model) wantsJSON(col, current string) bool {
+    if info, ok := m.fieldInfo(col); ok && strings.Contains(strings.ToUpper(info.Type), "JSON") {
+        return true
+    }
+    return looksLikeJSON(current)
+}
+
+// checkJSON asks SQLite whether s is valid JSON.
+func (m *model) checkJSON(col, s string) error {
+    var ok int64
+    if err := m.conn().QueryRow("SELECT json_valid(?)", s).Scan(&ok); err != nil {
+        return err
+    }
+    if ok != 1 {
+        return validationError{col, "not valid JSON"}
+    }
+    return nil
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "regexp"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// jsonNode is one value of a parsed JSON document. Object keys keep their
+// order from the source text.
+type jsonNode struct {
+    key       string // object key or array index label, empty for the root
+    path      string // SQLite JSON path, e.g. $.items[2].name
+    kind      string // object, array, string, number, bool, null
+    text      string // scalar value as JSON text
+    children  []*jsonNode
+    collapsed bool
+    depth     int
+}
+
+// looksLikeJSON reports whether s is a JSON object or array; plain scalars
+// like 5 or "x" are not treated as JSON documents.
+func looksLikeJSON(s string) bool {
+    t := strings.TrimSpace(s)
+    return (strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[")) && json.Valid([]byte(t))
+}
+
+// parseJSONTree parses s into a tree rooted at path "$".
+func parseJSONTree(s string) (*jsonNode, error) {
+    dec := json.NewDecoder(strings.NewReader(s))
+    dec.UseNumber()
+    root, err := decodeJSONNode(dec, "", "$", 0)
+    if err != nil {
+        return nil, err
+    }
+    if dec.More() {
+        return nil, fmt.Errorf("trailing data after JSON value")
+    }
+    return root, nil
+}
+
+func decodeJSONNode(dec *json.Decoder, key, path string, depth int) (*jsonNode, error) {
+    tok, err := dec.Token()
+    if err != nil {
+        return nil, err
+    }
+    n := &jsonNode{key: key, path: path, depth: depth}
+    switch t := tok.(type) {
+    case json.Delim:
+        if t == '{' {
+            n.kind = "object"
+            for dec.More() {
+                kt, err := dec.Token()
+                if err != nil {
+                    return nil, err
+                }
+                k := kt.(string)
+                c, err := decodeJSONNode(dec, k, path+jsonPathKey(k), depth+1)
+                if err != nil {
+                    return nil, err
+                }
+                n.children = append(n.children, c)
+            }
+        } else {
+            n.kind = "array"
+            for i := 0; dec.More(); i++ {
+                c, err := decodeJSONNode(dec, fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", path, i), depth+1)
+                if err != nil {
+                    return nil, err
+                }
+                n.children = append(n.children, c)
+            }
+        }
+        if _, err := dec.Token(); err != nil { // closing delimiter
+            return nil, err
+        }
+    case string:
+        n.kind = "string"
+        b, _ := json.Marshal(t)
+        n.text = string(b)
+    case json.Number:
+        n.kind, n.text = "number", t.String()
+    case bool:
+        n.kind, n.text = "bool", fmt.Sprint(t)
+    case nil:
+        n.kind, n.text = "null", "null"
+    }
+    return n, nil
+}
+
+var plainJSONKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonPathKey is the path step for object key k: .name, or ."odd key".
+func jsonPathKey(k string) string {
+    if plainJSONKey.MatchString(k) {
+        return "." + k
+    }
+    return `."` + strings.ReplaceAll(k, `"`, `\"`) + `"`
+}
+
+// visible flattens the tree into the nodes shown, skipping collapsed subtrees.
+func (n *jsonNode) visible(out []*jsonNode) []*jsonNode {
+    out = append(out, n)
+    if n.collapsed {
+        return out
+    }
+    for _, c := range n.children {
+        out = c.visible(out)
+    }
+    return out
+}
+
+// compact returns the node's value as compact JSON text.
+func (n *jsonNode) compact() string {
+    switch n.kind {
+    case "object", "array":
+        var parts []string
+        for _, c := range n.children {
+            if n.kind == "object" {
+                k, _ := json.Marshal(c.key)
+                parts = append(parts, string(k)+":"+c.compact())
+            } else {
+                parts = append(parts, c.compact())
+            }
+        }
+        if n.kind == "object" {
+            return "{" + strings.Join(parts, ",") + "}"
+        }
+        return "[" + strings.Join(parts, ",") + "]"
+    }
+    return n.text
+}
+
+// line renders the node as one tree line: marker, key and value or summary,
+// with syntax colours.
+func (n *jsonNode) line() string {
+    var b strings.Builder
+    b.WriteString(strings.Repeat("  ", n.depth))
+    switch {
+    case len(n.children) == 0:
+        b.WriteString("  ")
+    case n.collapsed:
+        b.WriteString(styleDim.Render("▸ "))
+    default:
+        b.WriteString(styleDim.Render("▾ "))
+    }
+    if n.key != "" {
+        if strings.HasPrefix(n.key, "[") {
+            b.WriteString(styleDim.Render(n.key) + " ")
+        } else {
+            k, _ := json.Marshal(n.key)
+            b.WriteString(styleJSONKey.Render(string(k)) + styleDim.Render(": "))
+        }
+    }
+    switch n.kind {
+    case "object":
+        b.WriteString(styleDim.Render(fmt.Sprintf("{%d}", len(n.children))))
+    case "array":
+        b.WriteString(styleDim.Render(fmt.Sprintf("[%d]", len(n.children))))
+    case "string":
+        b.WriteString(styleJSONString.Render(n.text))
+    case "number":
+        b.WriteString(styleJSONNumber.Render(n.text))
+    default:
+        b.WriteString(styleJSONLiteral.Render(n.text))
+    }
+    return b.String()
+}
+
+// jsonPrettyLines renders s fully expanded for the detail view, or nil when s
+// isn't a JSON document.
+func jsonPrettyLines(s string, width int) []string {
+    if !looksLikeJSON(s) {
+        return nil
+    }
+    root, err := parseJSONTree(s)
+    if err != nil {
+        return nil
+    }
+    var out []string
+    for _, n := range root.visible(nil) {
+        l := n.line()
+        if visibleWidth(l) <= width {
+            out = append(out, l)
+            continue
+        }
+        // too long for one line: wrap the plain text instead
+        out = append(out, wrapText(stripANSI(l), width)...)
+    }
+    return out
+}
+
+// openJSONView shows the selected detail field as a navigable JSON tree.
+func (m *model) openJSONView() {
+    cur, ok := m.selectedCell()
+    if !ok {
+        return
+    }
+    val := m.editSeed(cur)
+    if !looksLikeJSON(val) {
+        m.status = fmt.Sprintf("%s is not a JSON object or array", m.previewColumns[m.selCol])
+        return
+    }
+    root, err := parseJSONTree(val)
+    if err != nil {
+        m.status = fmt.Sprintf("json error: %v", err)
+        return
+    }
+    m.jsonRoot = root
+    m.jsonActive = true
+    m.jsonCursor = 0
+    m.jsonScroll = 0
+}
+
+// updateJSONView handles keys in the JSON tree: j/k move, Enter/Space toggle,
+// h/l collapse/expand (or go to parent), v adds the path as a grid column.
+func (m model) updateJSONView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    nodes := m.jsonRoot.visible(nil)
+    m.jsonCursor = min(m.jsonCursor, len(nodes)-1)
+    cur := nodes[m.jsonCursor]
+    switch m.actionFor(msg.String(), "JSON tree", "Any view") {
+    case "quit":
+        return m.quit()
+    case "back":
+        m.jsonActive = false
+        m.jsonRoot = nil
+        return m, nil
+    case "up":
+        if m.jsonCursor > 0 { m.jsonCursor-- }
+    case "down":
+        if m.jsonCursor+1 < len(nodes) { m.jsonCursor++ }
+    case "top":
+        m.jsonCursor = 0
+    case "bottom":
+        m.jsonCursor = len(nodes) - 1
+    case "page-down":
+        m.jsonCursor = min(len(nodes)-1, m.jsonCursor+max(1, m.detailHeight()/2))
+    case "page-up":
+        m.jsonCursor = max(0, m.jsonCursor-max(1, m.detailHeight()/2))
+    case "toggle":
+        if len(cur.children) > 0 { cur.collapsed = !cur.collapsed }
+    case "child":
+        if len(cur.children) > 0 {
+            if cur.collapsed {
+                cur.collapsed = false
+            } else if m.jsonCursor+1 < len(nodes) {
+                m.jsonCursor++ // step into the first child
+            }
+        }
+    case "parent":
+        if len(cur.children) > 0 && !cur.collapsed {
+            cur.collapsed = true
+        } else {
+            // jump to the parent node
+            for i := m.jsonCursor - 1; i >= 0; i-- {
+                if nodes[i].depth < cur.depth {
+                    m.jsonCursor = i
+                    break
+                }
+            }
+        }
+    case "copy":
+        if err := copyToClipboard(cur.compact()); err != nil {
+            m.status = fmt.Sprintf("copy error: %v", err)
+        } else {
+            m.status = "copied " + cur.path
+        }
+    case "copy-path":
+        if err := copyToClipboard(cur.path); err != nil {
+            m.status = fmt.Sprintf("copy error: %v", err)
+        } else {
+            m.status = "copied path " + cur.path
+        }
+    case "column":
+        col := m.previewColumns[m.selCol]
+        if err := m.addJSONColumn(col, cur.path); err != nil {
+            m.status = fmt.Sprintf("json column error: %v", err)
+        } else {
+            m.status = fmt.Sprintf("added column %s", jsonColumnName(col, cur.path))
+        }
+    }
+    // keep the cursor on screen
+    h := max(1, m.detailHeight()-2)
+    if m.jsonCursor < m.jsonScroll {
+        m.jsonScroll = m.jsonCursor
+    } else if m.jsonCursor >= m.jsonScroll+h {
+        m.jsonScroll = m.jsonCursor - h + 1
+    }
+    return m, nil
+}
+
+// renderJSONView draws the JSON tree of the selected field into b.
+func (m model) renderJSONView(b *strings.Builder, width int) {
+    nodes := m.jsonRoot.visible(nil)
+    cursor := min(m.jsonCursor, len(nodes)-1)
+    col := m.previewColumns[m.selCol]
+    b.WriteString(styleHeader.Render(fmt.Sprintf("JSON: %s.%s (%s)", m.previewTable, col, m.pagePosition())) + " " + styleSearch.Render(nodes[cursor].path) + "\n")
+    b.WriteString(styleDim.Render("j/k move, Enter/Space fold, h/l parent/child, y copy value, p copy path, v add as column, Esc back") + "\n")
+    h := max(1, m.detailHeight()-2)
+    for i := m.jsonScroll; i < len(nodes) && i < m.jsonScroll+h; i++ {
+        prefix := "  "
+        if i == cursor {
+            prefix = styleCursor.Render("> ")
+        }
+        b.WriteString(prefix + truncateANSI(nodes[i].line(), max(1, width-2)) + "\n")
+    }
+}
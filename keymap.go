@@ -0,0 +1,119 @@
+package main
+
+import (
+    "regexp"
+    "strings"
+)
+
+// keyActions are the actions whose keys can be remapped in the [keys]
+// section of the config file, with their default keys.
+var keyActions = []struct {
+    name string
+    def  string
+}{
+    {"quit", "q"},
+    {"edit", "c"},
+    {"delete", "x"},
+    {"insert", "i"},
+    {"copy", "y"},
+    {"reload", "r"},
+}
+
+// keymap holds the key bound to each remappable action.
+type keymap map[string]string
+
+func defaultKeymap() keymap {
+    k := make(keymap, len(keyActions))
+    for _, a := range keyActions {
+        k[a.name] = a.def
+    }
+    return k
+}
+
+// key is the key bound to action, for hints.
+func (k keymap) key(action string) string {
+    if s, ok := k[action]; ok {
+        return s
+    }
+    for _, a := range keyActions {
+        if a.name == action {
+            return a.def
+        }
+    }
+    return ""
+}
+
+var namedKeyRe = regexp.MustCompile(`^((ctrl|alt)\+[a-z0-9]|alt\+\S|f([1-9]|1[0-9]|20)|enter|tab|esc|space|backspace|delete|insert|home|end|pgup|pgdown|up|down|left|right)$`)
+
+// validKey reports whether s names a key as bubbletea spells it: a single
+// printable character, or a name like ctrl+y, alt+d, f2 or delete.
+func validKey(s string) bool {
+    if r := []rune(s); len(r) == 1 {
+        return r[0] > ' ' && r[0] != 0x7f
+    }
+    return namedKeyRe.MatchString(s)
+}
+
+// loadKeymap reads the [keys] section, e.g. quit = "Q". Each action takes
+// one key; unknown actions, malformed keys and clashes with other bindings
+// are errors.
+func loadKeymap(cfg *config) (keymap, error) {
+    k := defaultKeymap()
+    sec := cfg.sections["keys"]
+    // report the first unknown action in file order
+    bad, line := "", 0
+    for name, v := range sec {
+        if _, ok := k[name]; !ok && (bad == "" || v.line < line) { bad, line = name, v.line }
+    }
+    if bad != "" {
+        var names []string
+        for _, a := range keyActions {
+            names = append(names, a.name)
+        }
+        return nil, cfg.errorf(line, "unknown action %q in [keys] (have %s)", bad, strings.Join(names, ", "))
+    }
+    for _, a := range keyActions {
+        v, ok := sec[a.name]
+        if !ok {
+            continue
+        }
+        if !validKey(v.raw) {
+            return nil, cfg.errorf(v.line, "%s: %q is not a key (use a character or a name like ctrl+y, alt+d, f2, delete)", a.name, v.raw)
+        }
+        key := v.raw
+        if key == "space" { key = " " }
+        if containsString(fixedKeys(), key) {
+            return nil, cfg.errorf(v.line, "%s: %q is already bound to another action", a.name, v.raw)
+        }
+        k[a.name] = key
+    }
+    // two actions on one key, including a default that wasn't remapped away
+    seen := map[string]string{}
+    for _, a := range keyActions {
+        key := k[a.name]
+        if other, dup := seen[key]; dup {
+            line := sec[a.name].line
+            if line == 0 { line = sec[other].line }
+            return nil, cfg.errorf(line, "%s and %s are both bound to %q", other, a.name, key)
+        }
+        seen[key] = a.name
+    }
+    return k, nil
+}
+
+func containsString(list []string, s string) bool {
+    for _, x := range list {
+        if x == s {
+            return true
+        }
+    }
+    return false
+}
+
+// keyLabel is how a key is shown in hints.
+func keyLabel(key string) string {
+    if key == " " {
+        return "space"
+    }
+    return key
+}
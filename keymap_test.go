@@ -0,0 +1,60 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestValidKey(t *testing.T) {
+    for _, k := range []string{"q", "Q", "?", "ctrl+y", "alt+d", "alt+.", "f2", "f12", "delete", "space"} {
+        if !validKey(k) {
+            t.Errorf("validKey(%q) = false", k)
+        }
+    }
+    for _, k := range []string{"", " ", "ctrl+", "ctrl+shift+a", "f0", "f21", "Ctrl+Y", "qq"} {
+        if validKey(k) {
+            t.Errorf("validKey(%q) = true", k)
+        }
+    }
+}
+
+func TestLoadKeymap(t *testing.T) {
+    tests := []struct {
+        name    string
+        in      string
+        want    map[string]string
+        wantErr string
+    }{
+        {"defaults", "", map[string]string{"quit": "q", "copy": "y"}, ""},
+        {"remap", "[keys]\nquit = \"Q\"\ncopy = \"ctrl+y\"", map[string]string{"quit": "Q", "copy": "ctrl+y", "edit": "c"}, ""},
+        {"space", "[keys]\nedit = \"space\"", map[string]string{"edit": " "}, ""},
+        {"swap", "[keys]\nedit = \"i\"\ninsert = \"c\"", map[string]string{"edit": "i", "insert": "c"}, ""},
+        {"unknown action", "[keys]\nfly = \"f\"", nil, "cfg:2: unknown action \"fly\""},
+        {"not a key", "[keys]\nquit = \"ctrl+\"", nil, "cfg:2: quit: \"ctrl+\" is not a key"},
+        {"fixed key", "[keys]\ncopy = \"j\"", nil, "cfg:2: copy: \"j\" is already bound"},
+        {"fixed in a sub-view", "[keys]\nedit = \"ctrl+d\"", nil, "is already bound"},
+        {"clash with a default", "[keys]\ncopy = \"x\"", nil, "delete and copy are both bound to \"x\""},
+    }
+    for _, tt := range tests {
+        c, err := parseConfig("cfg", strings.NewReader(tt.in))
+        if err != nil {
+            t.Fatal(err)
+        }
+        k, err := loadKeymap(c)
+        if tt.wantErr != "" {
+            if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("%s: %v", tt.name, err)
+            continue
+        }
+        for action, key := range tt.want {
+            if k.key(action) != key {
+                t.Errorf("%s: %s = %q, want %q", tt.name, action, k.key(action), key)
+            }
+        }
+    }
+}
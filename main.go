@@ -1,6 +1,7 @@
 package main
 
 import (
+    "flag"
     "fmt"
     "os"
 
@@ -8,6 +9,41 @@ import (
 )
 
 func main() {
+    var opts options
+    flags := optionFlags(flag.CommandLine)
+    flag.StringVar(&opts.dbPath, "db", "", "database path; subcommands take the path here, the TUI also as its first argument")
+    flag.Usage = func() {
+        out := flag.CommandLine.Output()
+        fmt.Fprintf(out, "Usage: %s [flags] [db path]\n", appName)
+        fmt.Fprintf(out, "       %s [flags] [--db path] <command> [args]\n\nCommands:\n", appName)
+        for _, c := range cliCommands {
+            fmt.Fprintf(out, "  %-36s %s\n", c.usage, c.help)
+        }
+        fmt.Fprintln(out, "\nFlags (each also read from TUI_SQL_<NAME> or the config file):")
+        flag.PrintDefaults()
+    }
+    flag.Parse()
+    cfg, err := loadConfig()
+    if err == nil {
+        err = loadThemes(cfg)
+    }
+    if err == nil {
+        err = resolveOptions(&opts, flags, cfg)
+    }
+    if err == nil {
+        opts.keys, err = loadKeymap(cfg)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "%s: %v\n", appName, err)
+        os.Exit(2)
+    }
+    applyTheme(themes[opts.theme])
+    if c, ok := findCLICommand(flag.Arg(0)); ok {
+        os.Exit(runCLI(c, flag.Args()[1:], opts))
+    }
+    if opts.dbPath == "" {
+        opts.dbPath = flag.Arg(0)
+    }
     if len(os.Getenv("DEBUG")) > 0 {
         f, err := tea.LogToFile("debug.log", "debug")
         if err == nil {
@@ -15,11 +51,11 @@ func main() {
         }
     }
     // Pre-flight DB path check for a friendlier error before the TUI starts
-    if resolveDBPath() == "" {
+    if resolveDBPath(opts) == "" {
         fmt.Println("No SQLite .db file found. Usage: 'go run . <db path>' or set DB_PATH. Alternatively, place a .db in the current directory or in 'instance/'.")
         os.Exit(2)
     }
-    p := tea.NewProgram(initialModel())
+    p := tea.NewProgram(initialModel(opts))
     if _, err := p.Run(); err != nil {
         fmt.Printf("Error: %v\n", err)
         os.Exit(1)
@@ -9,6 +9,7 @@ import (
 
 type model struct {
     db              *sql.DB
+    dbPath          string
     allTables       []string
     tables          []string
     cursor          int
@@ -16,6 +17,27 @@ type model struct {
     previewColumns  []string
     tableCols       []colInfo
     previewRowIDs   []int64
+    // preview pagination (keyset on PK/rowid, offset for views)
+    previewTable    string
+    keyCols         []string
+    previewKeys     [][]any // raw key values for each preview row
+    pageStart       []any   // key of the first row on the page; nil = first page
+    pageOffset      int     // position of the first preview row within the table
+    pageSize        int
+    totalRows       int
+    // horizontal scrolling of preview columns
+    colOffset       int  // first scrollable column shown
+    freezePK        bool // keep PK columns pinned on the left
+    // preview ordering; empty sortCol means key order
+    sortCol         string
+    sortDesc        bool
+    // row filters combined into the preview WHERE clause
+    filters         []columnFilter
+    filterActive    bool   // prompting for a condition
+    filterCol       string // column the prompt applies to
+    filterBuffer    string
+    // virtual json_extract columns appended to the preview
+    jsonCols        []jsonColumn
     status          string
     width           int
     height          int
@@ -24,13 +46,80 @@ type model struct {
     focusPreview    bool
     selRow          int
     selCol          int
+    // schema tab for the selected table
+    schemaTab        bool
+    schema           *tableSchema
+    schemaScroll     int
+    // foreign key navigation
+    navStack         []navEntry
+    refsActive       bool
+    refs             []refTarget
+    refsCursor       int
+    // full-row detail view (field selection uses selCol)
+    detailActive     bool
+    detailScroll     int
+    // JSON tree of the selected detail field
+    jsonActive       bool
+    jsonRoot         *jsonNode
+    jsonCursor       int
+    jsonScroll       int
+    // hex viewer of the selected cell
+    hexActive        bool
+    hexData          []byte
+    hexScroll        int // first dump line shown
+    // ad-hoc SQL query pane
+    queryActive      bool
+    queryEditor      textArea
+    queryResult      *queryResult
+    queryFocusResult bool // results grid has focus instead of the editor
+    qSelRow          int
+    qSelCol          int
+    qColOffset       int
+    // query history browser
+    historyActive    bool
+    historyQuery     string
+    history          []historyEntry // newest first
+    historyMatches   []int          // indexes into history
+    historyCursor    int
     // inline cell edit state
     editingActive   bool
-    editBuffer      string
+    cellEditor      textArea
+    editErr         string // validation error shown while the editor stays open
+    // staging mode: edits collect in one transaction until commit/rollback
+    tx                *sql.Tx
+    pending           int
+    stagedCells       map[stagedCell]bool
+    confirmQuitActive bool
+    undoMark          int // undo stack depth when the staging transaction began
+    // undo/redo of data edits made through actions.go
+    undoStack         []undoEntry
+    redoStack         []undoEntry
+    lastInsertID      int64
     // table deletion confirm state
     confirmDeleteActive bool
     confirmDeleteTarget string
-    confirmDeleteType   string // "table" or "view"
+    confirmDeleteType   string // "table", "view" or "row"
+    trashEnabled        bool   // copy deleted rows into a per-table trash table
+    readOnly            bool   // database opened with --read-only
+    keys                keymap // remappable action keys from the config file
+    helpActive          bool
+    helpScroll          int
+    // command palette (Ctrl+P)
+    paletteActive       bool
+    paletteQuery        string
+    paletteCursor       int
+    paletteRecent       []string // recently run command names, newest first
+    opts                options  // connection options, to reopen the database
+    leftWidth           int    // table list width from --left-width; 0 means the default
+    // one-line status prompt (export path, ...)
+    promptActive        bool
+    promptKind          string
+    promptLabel         string
+    promptBuffer        string
+    importPath          string // file chosen in the import prompt
+    importTable         string
+    exportPath          string // existing file the export asks to overwrite
+    exportFromQuery     bool
 }
 
 type colInfo struct {
@@ -46,9 +135,43 @@ type uniqueIndex struct {
     Columns []string
 }
 
-func initialModel() model {
-    db, err := openDB()
-    m := model{db: db, status: ""}
+type indexInfo struct {
+    Name    string
+    Columns []string // "" for expression columns
+    Unique  bool
+    Partial bool
+    Origin  string // "c" (CREATE INDEX), "u" (UNIQUE), "pk"
+    SQL     string // empty for automatic indexes
+}
+
+type triggerInfo struct {
+    Name string
+    SQL  string
+}
+
+type foreignKey struct {
+    ID       int
+    Table    string // child table holding the key
+    RefTable string // parent table
+    From     []string
+    To       []string // "" means the parent's primary key
+    OnUpdate string
+    OnDelete string
+}
+
+// conn returns the open staging transaction when there is one, otherwise the
+// database itself. All reads and writes go through it so the preview shows
+// staged changes.
+func (m *model) conn() dbConn {
+    if m.tx != nil {
+        return m.tx
+    }
+    return m.db
+}
+
+func initialModel(opts options) model {
+    db, err := openDB(opts)
+    m := model{db: db, dbPath: resolveDBPath(opts), status: "", freezePK: true, trashEnabled: opts.trash, readOnly: opts.readOnly, pageSize: opts.pageSize, leftWidth: opts.leftWidth, keys: opts.keys, opts: opts}
     if err != nil {
         m.status = fmt.Sprintf("db open error: %v", err)
         return m
@@ -60,53 +183,306 @@ func initialModel() model {
     sort.Strings(tables)
     m.allTables = tables
     m.applyFilter()
+    if opts.table != "" && !m.selectTable(opts.table) {
+        m.status = fmt.Sprintf("table %q not found", opts.table)
+    }
     return m
 }
 
+// defaultPageSize is the number of rows shown per preview page.
+const defaultPageSize = 10
+
 func (m *model) refreshPreview() {
     m.preview = nil
     m.previewColumns = nil
     m.previewRowIDs = nil
+    m.previewKeys = nil
     // Load table info for PK detection
     m.tableCols = nil
+    m.keyCols = nil
     if len(m.tables) > 0 && m.cursor >= 0 && m.cursor < len(m.tables) && m.db != nil {
-        if ti, err := getTableInfo(m.db, m.tables[m.cursor]); err == nil {
+        if ti, err := getTableInfo(m.conn(), m.tables[m.cursor]); err == nil {
             m.tableCols = ti
         } else {
             m.status = fmt.Sprintf("table info error: %v", err)
         }
     }
     if m.db == nil || len(m.tables) == 0 || m.cursor < 0 || m.cursor >= len(m.tables) {
+        m.previewTable = ""
         return
     }
     tbl := m.tables[m.cursor]
-    // Preview: limit 10 rows; include rowid if no explicit PK present
-    needsRowid := !hasExplicitPK(m.tableCols)
-    q := ""
-    if needsRowid {
-        q = fmt.Sprintf("SELECT rowid, * FROM %s LIMIT 10", quoteIdent(tbl))
-    } else {
-        q = fmt.Sprintf("SELECT * FROM %s LIMIT 10", quoteIdent(tbl))
+    if tbl != m.previewTable {
+        // switching tables: start from the first page
+        m.previewTable = tbl
+        m.resetPage()
+        m.colOffset = 0
+        m.sortCol, m.sortDesc = "", false
+        m.filters = nil
+        m.jsonCols = nil
+        m.detailActive = false
+        m.jsonActive = false
     }
-    rows, err := m.db.Query(q)
-    if err != nil {
+    if m.schemaTab && (m.schema == nil || m.schema.Table != tbl) {
+        m.loadSchema()
+    }
+    m.keyCols = m.pageKeyColumns(tbl)
+    if err := m.countPreviewRows(); err != nil {
+        m.status = fmt.Sprintf("count error: %v", err)
+    }
+    // Reload the current page starting at its first row (inclusive)
+    if err := m.loadPage(m.pageStart, true, false); err != nil {
         m.status = fmt.Sprintf("preview error: %v", err)
         return
     }
+    if len(m.preview) == 0 && m.pageOffset > 0 {
+        // rows under the page disappeared (e.g. deletes); fall back to the last page
+        if err := m.lastPage(); err != nil {
+            m.status = fmt.Sprintf("preview error: %v", err)
+            return
+        }
+    }
+    m.clampSelection()
+}
+
+// clampSelection keeps selRow/selCol inside the loaded preview page.
+func (m *model) clampSelection() {
+    if m.selRow >= len(m.preview) {
+        m.selRow = max(0, len(m.preview)-1)
+    }
+    if m.selCol >= len(m.previewColumns) {
+        m.selCol = max(0, len(m.previewColumns)-1)
+    }
+}
+
+// frozenColumns returns the preview column indexes pinned to the left of the
+// grid: the explicit PK columns when freezePK is on.
+func (m model) frozenColumns() []int {
+    if !m.freezePK {
+        return nil
+    }
+    var out []int
+    for _, c := range m.tableCols {
+        if c.PKOrder == 0 { continue }
+        if idx := findColIndex(m.previewColumns, c.Name); idx >= 0 {
+            out = append(out, idx)
+        }
+    }
+    sort.Ints(out)
+    return out
+}
+
+// resetPage moves pagination back to the first page of the current table.
+func (m *model) resetPage() {
+    m.pageStart = nil
+    m.pageOffset = 0
+    m.selRow = 0
+}
+
+// pageKeyColumns returns the key used for keyset pagination: the explicit PK
+// columns in PK order, or rowid when there is none. Views have neither and
+// return nil, in which case paging falls back to LIMIT/OFFSET.
+//
+// Rowid tables accept NULL in a PK that isn't INTEGER PRIMARY KEY, and the
+// row-value comparison behind keyset paging never matches NULL, so such rows
+// would drop out of every page; when the table holds any, rowid is the key.
+func (m *model) pageKeyColumns(table string) []string {
+    var pk []colInfo
+    for _, c := range m.tableCols {
+        if c.PKOrder > 0 { pk = append(pk, c) }
+    }
+    if len(pk) > 0 && !m.hasNullPK(table, pk) {
+        sort.Slice(pk, func(i, j int) bool { return pk[i].PKOrder < pk[j].PKOrder })
+        out := make([]string, len(pk))
+        for i, c := range pk { out[i] = c.Name }
+        return out
+    }
+    if typ, err := getObjectType(m.conn(), table); err == nil && typ == "view" {
+        return nil
+    }
+    return []string{"rowid"}
+}
+
+// hasNullPK reports whether a row of table has NULL in one of the pk columns.
+func (m *model) hasNullPK(table string, pk []colInfo) bool {
+    var terms []string
+    for _, c := range pk {
+        if !c.NotNull { terms = append(terms, quoteIdent(c.Name)+" IS NULL") }
+    }
+    if len(terms) == 0 || !hasRowid(m.conn(), table) {
+        return false // WITHOUT ROWID tables enforce NOT NULL on the PK
+    }
+    var n int
+    q := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s WHERE %s LIMIT 1)", quoteIdent(table), strings.Join(terms, " OR "))
+    return m.conn().QueryRow(q).Scan(&n) == nil && n > 0
+}
+
+func (m *model) countPreviewRows() error {
+    m.totalRows = 0
+    q := fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdent(m.previewTable))
+    terms, params := m.filterWhere()
+    if len(terms) > 0 {
+        q += " WHERE " + strings.Join(terms, " AND ")
+    }
+    return m.conn().QueryRow(q, params...).Scan(&m.totalRows)
+}
+
+func (m *model) pageLimit() int {
+    if m.pageSize > 0 { return m.pageSize }
+    return defaultPageSize
+}
+
+// keyExprs returns the quoted key columns for use in SELECT/ORDER BY.
+func (m *model) keyExprs() []string {
+    out := make([]string, len(m.keyCols))
+    for i, c := range m.keyCols {
+        if c == "rowid" { out[i] = "rowid" } else { out[i] = quoteIdent(c) }
+    }
+    return out
+}
+
+// sortDirection is the SQL direction of the preview sort ("ASC" unless sorting
+// descending); reverse flips it for walking backwards.
+func (m *model) sortDirection(reverse bool) string {
+    if m.sortDesc != reverse {
+        return "DESC"
+    }
+    return "ASC"
+}
+
+// pageQuery builds the SELECT for one preview page. The page cursor (sort value,
+// if sorting, then the key columns) is selected ahead of the table columns;
+// hidden is how many such leading columns there are.
+func (m *model) pageQuery(from []any, inclusive, reverse bool) (string, []any, int) {
+    keys := m.keyExprs()
+    dir := m.sortDirection(reverse)
+    // the cursor is read raw so it binds back as the stored values
+    var cursor []string
+    if m.sortCol != "" && len(keys) > 0 {
+        cursor = append(cursor, rawExpr(m.sortCol))
+    }
+    for _, c := range m.keyCols {
+        cursor = append(cursor, rawExpr(c))
+    }
+    star := "*"
+    if extra := m.jsonSelectExprs(); len(extra) > 0 {
+        star += ", " + strings.Join(extra, ", ")
+    }
+    selectList := star
+    if len(keys) > 0 {
+        selectList = strings.Join(cursor, ", ") + ", " + star
+    }
+    q := fmt.Sprintf("SELECT %s FROM %s", selectList, quoteIdent(m.previewTable))
+    where, params := m.filterWhere()
+    var order []string
+    if m.sortCol != "" {
+        order = append(order, quoteIdent(m.sortCol)+" "+dir)
+    }
+    if len(keys) == 0 {
+        // No usable key (views): plain offset paging
+        if len(where) > 0 {
+            q += " WHERE " + strings.Join(where, " AND ")
+        }
+        if len(order) > 0 {
+            q += " ORDER BY " + strings.Join(order, ", ")
+        }
+        q += " LIMIT ? OFFSET ?"
+        params = append(params, m.pageLimit(), m.pageOffset)
+        return q, params, 0
+    }
+    if from != nil {
+        pred, p := keysetPredicate(m.sortCol, keys, from, dir == "DESC", inclusive)
+        where = append(where, pred)
+        params = append(params, p...)
+    }
+    if len(where) > 0 {
+        q += " WHERE " + strings.Join(where, " AND ")
+    }
+    for _, k := range keys {
+        order = append(order, k+" "+dir)
+    }
+    q += " ORDER BY " + strings.Join(order, ", ") + " LIMIT ?"
+    params = append(params, m.pageLimit())
+    return q, params, len(cursor)
+}
+
+// keysetPredicate matches the rows that come after the cursor from in the
+// given direction (or at it, when inclusive). from holds the sort value first
+// when sortCol is set, followed by the key values. NULL sort values are
+// ordered the way SQLite does: first ascending, last descending.
+func keysetPredicate(sortCol string, keys []string, from []any, desc, inclusive bool) (string, []any) {
+    op := ">"
+    if desc { op = "<" }
+    if inclusive { op += "=" }
+    keyVals := from
+    if sortCol != "" {
+        keyVals = from[1:]
+    }
+    keyPred := fmt.Sprintf("(%s) %s (%s)", strings.Join(keys, ", "), op, placeholders(len(keys)))
+    if sortCol == "" {
+        return keyPred, keyVals
+    }
+    c := quoteIdent(sortCol)
+    v := from[0]
+    params := make([]any, 0, len(from)+1)
+    if v == nil {
+        params = append(params, keyVals...)
+        if desc {
+            return fmt.Sprintf("(%s IS NULL AND %s)", c, keyPred), params
+        }
+        return fmt.Sprintf("((%s IS NULL AND %s) OR %s IS NOT NULL)", c, keyPred, c), params
+    }
+    cmp := ">"
+    if desc { cmp = "<" }
+    params = append(params, v, v)
+    params = append(params, keyVals...)
+    pred := fmt.Sprintf("(%s %s ? OR (%s = ? AND %s)", c, cmp, c, keyPred)
+    if desc {
+        pred += fmt.Sprintf(" OR %s IS NULL", c)
+    }
+    return pred + ")", params
+}
+
+// cycleSort moves the sort on column col through ascending, descending and
+// unsorted, then reloads the preview from its first page.
+func (m *model) cycleSort(col string) {
+    switch {
+    case !strings.EqualFold(m.sortCol, col):
+        m.sortCol, m.sortDesc = col, false
+    case !m.sortDesc:
+        m.sortDesc = true
+    default:
+        m.sortCol, m.sortDesc = "", false
+    }
+    m.resetPage()
+    m.refreshPreview()
+}
+
+// loadPage queries one page of preview rows. When from is non-nil, only rows
+// whose key follows it (or equals it, if inclusive) are returned. reverse walks
+// the key order backwards, which is how earlier pages are fetched; rows are
+// always stored in display (ascending) order.
+func (m *model) loadPage(from []any, inclusive, reverse bool) error {
+    q, params, hidden := m.pageQuery(from, inclusive, reverse)
+    rows, err := m.conn().Query(q, params...)
+    if err != nil {
+        return err
+    }
     defer rows.Close()
 
     cols, err := rows.Columns()
     if err != nil {
-        m.status = fmt.Sprintf("columns error: %v", err)
-        return
+        return fmt.Errorf("columns: %w", err)
     }
-    if needsRowid && len(cols) > 0 && strings.EqualFold(cols[0], "rowid") {
-        m.previewColumns = cols[1:]
-    } else {
-        m.previewColumns = cols
-        needsRowid = false // defensive
+    if len(cols) < hidden {
+        return fmt.Errorf("unexpected column count %d", len(cols))
     }
+    usesRowid := len(m.keyCols) == 1 && m.keyCols[0] == "rowid"
+    rowidAt := hidden - len(m.keyCols) // the key follows the sort value, if any
 
+    var recs [][]string
+    var keyVals [][]any
+    var rowids []int64
     for rows.Next() {
         raw := make([]any, len(cols))
         dest := make([]any, len(cols))
@@ -114,31 +490,113 @@ func (m *model) refreshPreview() {
             dest[i] = &raw[i]
         }
         if err := rows.Scan(dest...); err != nil {
-            m.status = fmt.Sprintf("scan error: %v", err)
-            return
+            return fmt.Errorf("scan: %w", err)
         }
-        start := 0
-        if needsRowid {
-            // capture rowid and skip it in display
-            m.previewRowIDs = append(m.previewRowIDs, asInt64(raw[0]))
-            start = 1
+        // capture key values and skip them in display
+        keyVals = append(keyVals, append([]any(nil), raw[:hidden]...))
+        if usesRowid {
+            rowids = append(rowids, asInt64(raw[rowidAt]))
         }
-        rec := make([]string, len(cols)-start)
-        for i := start; i < len(raw); i++ {
-            rec[i-start] = formatValue(raw[i])
+        rec := make([]string, len(cols)-hidden)
+        for i := hidden; i < len(raw); i++ {
+            rec[i-hidden] = formatValue(raw[i])
         }
-        m.preview = append(m.preview, rec)
+        recs = append(recs, rec)
     }
     if err := rows.Err(); err != nil {
-        m.status = fmt.Sprintf("rows error: %v", err)
+        return fmt.Errorf("rows: %w", err)
     }
-    // Clamp selection indexes
-    if m.selRow >= len(m.preview) {
-        m.selRow = max(0, len(m.preview)-1)
+    if reverse {
+        reverseRows(recs)
+        reverseRows(keyVals)
+        reverseRows(rowids)
     }
-    if m.selCol >= len(m.previewColumns) {
-        m.selCol = max(0, len(m.previewColumns)-1)
+    m.previewColumns = cols[hidden:]
+    m.preview = recs
+    m.previewKeys = keyVals
+    if usesRowid {
+        m.previewRowIDs = rowids
+    } else {
+        m.previewRowIDs = nil
+    }
+    return nil
+}
+
+// nextPage advances the preview by one page; it is a no-op on the last page.
+func (m *model) nextPage() error {
+    if len(m.preview) == 0 || m.pageOffset+len(m.preview) >= m.totalRows {
+        return nil
+    }
+    shown := len(m.preview)
+    if m.keyCols == nil {
+        m.pageOffset += shown
+        return m.loadPage(nil, false, false)
+    }
+    if err := m.loadPage(m.previewKeys[shown-1], false, false); err != nil {
+        return err
+    }
+    if len(m.preview) > 0 {
+        m.pageStart = m.previewKeys[0]
+        m.pageOffset += shown
+    }
+    return nil
+}
+
+// prevPage moves the preview back by one page.
+func (m *model) prevPage() error {
+    if m.pageOffset == 0 {
+        return nil
+    }
+    if m.keyCols == nil || m.pageOffset <= m.pageLimit() || len(m.previewKeys) == 0 {
+        m.pageOffset = max(0, m.pageOffset-m.pageLimit())
+        if m.pageOffset == 0 {
+            return m.firstPage()
+        }
+        return m.loadPage(nil, false, false)
+    }
+    if err := m.loadPage(m.previewKeys[0], false, true); err != nil {
+        return err
+    }
+    if len(m.preview) > 0 {
+        m.pageStart = m.previewKeys[0]
+        m.pageOffset = max(0, m.pageOffset-len(m.preview))
+    }
+    return nil
+}
+
+func (m *model) firstPage() error {
+    m.pageStart = nil
+    m.pageOffset = 0
+    return m.loadPage(nil, false, false)
+}
+
+func (m *model) lastPage() error {
+    if m.keyCols == nil {
+        m.pageOffset = max(0, m.totalRows-m.pageLimit())
+        return m.loadPage(nil, false, false)
+    }
+    if err := m.loadPage(nil, false, true); err != nil {
+        return err
+    }
+    m.pageStart = nil
+    m.pageOffset = 0
+    if len(m.preview) > 0 {
+        m.pageStart = m.previewKeys[0]
+        m.pageOffset = max(0, m.totalRows-len(m.preview))
     }
+    return nil
+}
+
+// gotoPage runs a paging function and reports any failure in the status line.
+func (m *model) gotoPage(fn func() error) {
+    if m.db == nil || m.previewTable == "" {
+        return
+    }
+    if err := fn(); err != nil {
+        m.status = fmt.Sprintf("preview error: %v", err)
+        return
+    }
+    m.clampSelection()
 }
 
 func (m *model) applyFilter() {
@@ -163,12 +621,3 @@ func (m *model) applyFilter() {
     }
     m.refreshPreview()
 }
-
-func hasExplicitPK(cols []colInfo) bool {
-    for _, c := range cols {
-        if c.PKOrder > 0 {
-            return true
-        }
-    }
-    return false
-}
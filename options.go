@@ -0,0 +1,181 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// options are the settings the program starts with, resolved from flags,
+// environment variables and the config file.
+type options struct {
+    readOnly    bool
+    dbPath      string // --db or the first argument; empty falls back to DB_PATH and auto-discovery
+    busyTimeout time.Duration
+    journalMode string // empty leaves the database's journal mode alone
+    foreignKeys bool
+    trash       bool   // soft delete into _trash_<table> tables
+    table       string // table or view to open at startup
+    pageSize    int
+    leftWidth   int
+    theme       string
+    keys        keymap // from the [keys] section of the config file
+}
+
+// optionSpec describes one setting. Its value comes from the --name flag when
+// given, else the env variable, else the config key (name with _ for -),
+// else def.
+type optionSpec struct {
+    name   string
+    env    string
+    def    string
+    usage  string
+    isBool bool
+    apply  func(o *options, v string) error
+}
+
+var optionSpecs = []optionSpec{
+    {"read-only", "TUI_SQL_READ_ONLY", "false", "open the database read-only (mode=ro, query_only)", true, func(o *options, v string) error {
+        return setBoolOption(&o.readOnly, v)
+    }},
+    {"busy-timeout", "TUI_SQL_BUSY_TIMEOUT", "5s", "wait up to `duration` for a locked database, e.g. 500ms (a bare number is milliseconds)", false, func(o *options, v string) error {
+        if n, err := strconv.Atoi(v); err == nil {
+            v = strconv.Itoa(n) + "ms"
+        }
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return fmt.Errorf("%q is not a duration like 5s or 500ms", v)
+        }
+        o.busyTimeout = d
+        return nil
+    }},
+    {"journal-mode", "TUI_SQL_JOURNAL_MODE", "", "set PRAGMA journal_mode to `mode`: delete, truncate, persist, memory, wal or off", false, func(o *options, v string) error {
+        v = strings.ToLower(v)
+        switch v {
+        case "", "delete", "truncate", "persist", "memory", "wal", "off":
+            o.journalMode = v
+            return nil
+        }
+        return fmt.Errorf("%q is not a journal mode (delete, truncate, persist, memory, wal or off)", v)
+    }},
+    {"foreign-keys", "TUI_SQL_FOREIGN_KEYS", "false", "enforce foreign key constraints (PRAGMA foreign_keys)", true, func(o *options, v string) error {
+        return setBoolOption(&o.foreignKeys, v)
+    }},
+    {"trash", "TUI_SQL_TRASH", "false", "soft delete: copy deleted rows into a _trash_<table> table first", true, func(o *options, v string) error {
+        return setBoolOption(&o.trash, v)
+    }},
+    {"table", "TUI_SQL_TABLE", "", "open `table` (or view) at startup", false, func(o *options, v string) error {
+        o.table = v
+        return nil
+    }},
+    {"page-size", "TUI_SQL_PAGE_SIZE", strconv.Itoa(defaultPageSize), "preview `rows` per page", false, func(o *options, v string) error {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 1 || n > maxQueryRows {
+            return fmt.Errorf("%q is not a page size between 1 and %d", v, maxQueryRows)
+        }
+        o.pageSize = n
+        return nil
+    }},
+    {"left-width", "TUI_SQL_LEFT_WIDTH", strconv.Itoa(defaultLeftWidth), "width of the table list in `columns`", false, func(o *options, v string) error {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 10 || n > 200 {
+            return fmt.Errorf("%q is not a width between 10 and 200", v)
+        }
+        o.leftWidth = n
+        return nil
+    }},
+    {"theme", "TUI_SQL_THEME", "default", "color `theme`: " + strings.Join(themeNames(), ", "), false, func(o *options, v string) error {
+        if _, ok := themes[v]; !ok {
+            return fmt.Errorf("unknown theme %q (have %s)", v, strings.Join(themeNames(), ", "))
+        }
+        o.theme = v
+        return nil
+    }},
+}
+
+func setBoolOption(dst *bool, v string) error {
+    b, ok := parseBool(v)
+    if !ok {
+        return fmt.Errorf("%q is not a boolean (true/false, 1/0, yes/no)", v)
+    }
+    *dst = b
+    return nil
+}
+
+// configKey is the config file key for an option name.
+func (s optionSpec) configKey() string { return strings.ReplaceAll(s.name, "-", "_") }
+
+// optionValue is the flag.Value behind every option flag; set records that
+// the flag was given, so an explicit --foo=false still beats the env and
+// config file.
+type optionValue struct {
+    s      string
+    set    bool
+    isBool bool
+}
+
+func (v *optionValue) String() string {
+    if v == nil {
+        return ""
+    }
+    return v.s
+}
+
+func (v *optionValue) Set(s string) error {
+    v.s, v.set = s, true
+    return nil
+}
+
+func (v *optionValue) IsBoolFlag() bool { return v.isBool }
+
+// optionFlags registers a flag for every option on fs.
+func optionFlags(fs *flag.FlagSet) map[string]*optionValue {
+    out := make(map[string]*optionValue, len(optionSpecs))
+    for _, s := range optionSpecs {
+        v := &optionValue{isBool: s.isBool}
+        if !s.isBool { v.s = s.def } // PrintDefaults shows non-empty defaults
+        fs.Var(v, s.name, s.usage)
+        out[s.name] = v
+    }
+    return out
+}
+
+// resolveOptions fills in every option from its flag, env variable, config
+// file key or default, in that order, and validates the chosen value.
+// Errors name where the bad value came from.
+func resolveOptions(opts *options, flags map[string]*optionValue, cfg *config) error {
+    known := make(map[string]bool, len(optionSpecs))
+    for _, s := range optionSpecs {
+        known[s.configKey()] = true
+        val, from := s.def, "default"
+        if cv, ok := cfg.values[s.configKey()]; ok {
+            val, from = cv.raw, fmt.Sprintf("%s:%d: %s", cfg.path, cv.line, s.configKey())
+        }
+        if ev, ok := os.LookupEnv(s.env); ok && ev != "" {
+            val, from = ev, s.env
+        }
+        if fv := flags[s.name]; fv != nil && fv.set {
+            val, from = fv.s, "--"+s.name
+        }
+        if err := s.apply(opts, strings.TrimSpace(val)); err != nil {
+            return fmt.Errorf("%s: %v", from, err)
+        }
+    }
+    // report the first unknown key or section in file order
+    bad, line := "", 0
+    for k, v := range cfg.values {
+        if !known[k] && (bad == "" || v.line < line) { bad, line = "setting "+k, v.line }
+    }
+    for _, sec := range cfg.order {
+        if bad == "" && sec != "keys" && !strings.HasPrefix(sec, "themes.") {
+            bad, line = "section ["+sec+"] (have [keys] and [themes.<name>])", cfg.sectionLines[sec]
+        }
+    }
+    if bad != "" {
+        return cfg.errorf(line, "unknown %s", bad)
+    }
+    return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+    "flag"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestResolveOptions(t *testing.T) {
+    tests := []struct {
+        name    string
+        args    []string
+        env     string // TUI_SQL_PAGE_SIZE
+        config  string
+        want    int
+        wantErr string
+    }{
+        {"default", nil, "", "", defaultPageSize, ""},
+        {"config", nil, "", "page_size = 20", 20, ""},
+        {"env over config", nil, "30", "page_size = 20", 30, ""},
+        {"flag over env", []string{"--page-size", "40"}, "30", "page_size = 20", 40, ""},
+        {"bad flag", []string{"--page-size=0"}, "", "", 0, "--page-size"},
+        {"bad config value", nil, "", "\n\npage_size = \"many\"", 0, "cfg:3: page_size"},
+        {"unknown setting", nil, "", "page_size = 5\nbogus = 1", 0, "cfg:2: unknown setting bogus"},
+        {"unknown section", nil, "", "[colors]", 0, "cfg:1: unknown section [colors]"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv("TUI_SQL_PAGE_SIZE", tt.env)
+            fs := flag.NewFlagSet("test", flag.ContinueOnError)
+            flags := optionFlags(fs)
+            if err := fs.Parse(tt.args); err != nil {
+                t.Fatal(err)
+            }
+            cfg, err := parseConfig("cfg", strings.NewReader(tt.config))
+            if err != nil {
+                t.Fatal(err)
+            }
+            var opts options
+            err = resolveOptions(&opts, flags, cfg)
+            if tt.wantErr != "" {
+                if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                    t.Fatalf("err = %v, want one mentioning %q", err, tt.wantErr)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if opts.pageSize != tt.want {
+                t.Errorf("pageSize = %d, want %d", opts.pageSize, tt.want)
+            }
+        })
+    }
+}
+
+func TestBusyTimeoutOption(t *testing.T) {
+    tests := []struct {
+        in   string
+        want time.Duration
+        ok   bool
+    }{
+        {"250", 250 * time.Millisecond, true},
+        {"2s", 2 * time.Second, true},
+        {"-1s", 0, false},
+        {"soon", 0, false},
+    }
+    for _, tt := range tests {
+        var opts options
+        fs := flag.NewFlagSet("test", flag.ContinueOnError)
+        flags := optionFlags(fs)
+        if err := fs.Parse([]string{"--busy-timeout", tt.in}); err != nil {
+            t.Fatal(err)
+        }
+        err := resolveOptions(&opts, flags, newConfig(""))
+        if (err == nil) != tt.ok || tt.ok && opts.busyTimeout != tt.want {
+            t.Errorf("--busy-timeout %s: got %v, %v", tt.in, opts.busyTimeout, err)
+        }
+    }
+}
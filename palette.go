@@ -0,0 +1,395 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxRecentCommands is how many palette commands are remembered as recent.
+const maxRecentCommands = 20
+
+// paletteCommand is one entry of the command palette. Commands with a key
+// (or a remappable action) replay that key press through Update, so they
+// behave exactly like the binding; the rest run their own func.
+type paletteCommand struct {
+    name   string
+    key    string
+    action string
+    when   func(m model) bool // nil means always available
+    run    func(m *model)
+}
+
+func previewFocused(m model) bool { return m.focusPreview && len(m.preview) > 0 }
+
+func listFocused(m model) bool { return !m.focusPreview && len(m.tables) > 0 }
+
+func staging(m model) bool { return m.tx != nil }
+
+// paletteCommands lists the commands available in the current state, with
+// one "Go to table" entry per table.
+func (m model) paletteCommands() []paletteCommand {
+    all := []paletteCommand{
+        {name: "Export table to file", key: "E", when: func(m model) bool { return m.previewTable != "" }},
+        {name: "Import file", key: "I"},
+        {name: "Open SQL query pane", key: ":"},
+        {name: "Query history", run: func(m *model) {
+            m.queryActive = true
+            m.openHistory()
+        }},
+        {name: "Toggle schema tab", key: "tab"},
+        {name: "Vacuum database", run: (*model).vacuum},
+        {name: "Integrity check", run: func(m *model) { m.runCheck("PRAGMA integrity_check", "integrity check") }},
+        {name: "Foreign key check", run: func(m *model) { m.runCheck("PRAGMA foreign_key_check", "foreign key check") }},
+        {name: "Analyze (update query planner statistics)", run: (*model).analyze},
+        {name: "Toggle read-only", run: (*model).toggleReadOnly},
+        {name: "Toggle staging mode", key: "T"},
+        {name: "Commit staged changes", key: "C", when: staging},
+        {name: "Roll back staged changes", key: "X", when: staging},
+        {name: "Undo", key: "u"},
+        {name: "Redo", key: "ctrl+r"},
+        {name: "Reload table list", action: "reload"},
+        {name: "Go back", key: "b"},
+        {name: "Search tables", key: "/", when: listFocused},
+        {name: "Drop table or view", action: "delete", when: listFocused},
+        {name: "Open row details", key: "enter", when: previewFocused},
+        {name: "Edit cell", action: "edit", when: previewFocused},
+        {name: "Edit cell in $EDITOR", key: "e", when: previewFocused},
+        {name: "Copy cell", action: "copy", when: previewFocused},
+        {name: "Hex view of cell", key: "B", when: previewFocused},
+        {name: "Insert row", action: "insert", when: func(m model) bool { return m.focusPreview }},
+        {name: "Delete row", action: "delete", when: previewFocused},
+        {name: "Restore row from trash", key: "t", when: func(m model) bool { return previewFocused(m) && isTrashTable(m.previewTable) }},
+        {name: "Sort by column", key: "s", when: previewFocused},
+        {name: "Filter column", key: "f", when: previewFocused},
+        {name: "Remove filter", key: "F", when: func(m model) bool { return m.focusPreview && len(m.filters) > 0 }},
+        {name: "Pin / unpin primary key columns", key: "p", when: previewFocused},
+        {name: "Follow foreign key", key: "o", when: previewFocused},
+        {name: "Rows referencing this row", key: "R", when: previewFocused},
+        {name: "Add or remove JSON column", key: "J", when: previewFocused},
+        {name: "First page", key: "g", when: previewFocused},
+        {name: "Last page", key: "G", when: previewFocused},
+        {name: "Help", key: "?"},
+        {name: "Quit", action: "quit"},
+    }
+    var out []paletteCommand
+    for _, c := range all {
+        if c.when == nil || c.when(m) {
+            out = append(out, c)
+        }
+    }
+    for _, t := range m.allTables {
+        t := t
+        out = append(out, paletteCommand{name: "Go to table " + t, run: func(m *model) {
+            if m.selectTable(t) {
+                m.status = "opened " + t
+            } else {
+                m.status = fmt.Sprintf("table %q not found", t)
+            }
+        }})
+    }
+    return out
+}
+
+// keyHint is the key shown next to a command, if it has one.
+func (c paletteCommand) keyHint(k keymap) string {
+    key := c.key
+    if c.action != "" {
+        key = k.key(c.action)
+    }
+    if key == "" {
+        return ""
+    }
+    return helpKeyName(key)
+}
+
+// paletteMatches returns the commands matching the palette query: recently
+// used ones first, most recent first, then the rest best match first.
+func (m model) paletteMatches() []paletteCommand {
+    cmds := m.paletteCommands()
+    recent := make(map[string]int, len(m.paletteRecent))
+    for i, name := range m.paletteRecent {
+        recent[name] = i
+    }
+    type scored struct {
+        cmd   paletteCommand
+        score int
+    }
+    var hits []scored
+    for _, c := range cmds {
+        if score, ok := fuzzyMatch(m.paletteQuery, c.name); ok {
+            hits = append(hits, scored{c, score})
+        }
+    }
+    sort.SliceStable(hits, func(a, b int) bool {
+        ra, aRecent := recent[hits[a].cmd.name]
+        rb, bRecent := recent[hits[b].cmd.name]
+        switch {
+        case aRecent && bRecent:
+            return ra < rb
+        case aRecent != bRecent:
+            return aRecent
+        }
+        return hits[a].score > hits[b].score
+    })
+    out := make([]paletteCommand, len(hits))
+    for i, h := range hits {
+        out[i] = h.cmd
+    }
+    return out
+}
+
+// openPalette shows the command palette with an empty query.
+func (m *model) openPalette() {
+    m.paletteActive = true
+    m.paletteQuery = ""
+    m.paletteCursor = 0
+    m.paletteRecent, _ = loadRecentCommands()
+}
+
+// updatePalette handles keys in the palette: typing narrows the list, arrows
+// move and Enter runs the selected command.
+func (m model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    matches := m.paletteMatches()
+    switch msg.Type {
+    case tea.KeyRunes, tea.KeySpace:
+        m.paletteQuery += string(msg.Runes)
+        m.paletteCursor = 0
+    case tea.KeyBackspace:
+        if r := []rune(m.paletteQuery); len(r) > 0 {
+            m.paletteQuery = string(r[:len(r)-1])
+            m.paletteCursor = 0
+        }
+    case tea.KeyUp, tea.KeyCtrlP:
+        if m.paletteCursor > 0 { m.paletteCursor-- }
+    case tea.KeyDown, tea.KeyCtrlN:
+        if m.paletteCursor+1 < len(matches) { m.paletteCursor++ }
+    case tea.KeyEsc, tea.KeyCtrlC:
+        m.paletteActive = false
+        m.status = ""
+    case tea.KeyEnter:
+        m.paletteActive = false
+        if m.paletteCursor >= len(matches) {
+            return m, nil
+        }
+        c := matches[m.paletteCursor]
+        if err := m.rememberCommand(c.name); err != nil {
+            m.status = fmt.Sprintf("recent commands error: %v", err)
+        }
+        if c.run != nil {
+            c.run(&m)
+            return m, nil
+        }
+        key := c.key
+        if c.action != "" {
+            key = m.keys.key(c.action)
+        }
+        return m.Update(keyMsg(key))
+    }
+    return m, nil
+}
+
+// rememberCommand moves name to the front of the recent commands and saves them.
+func (m *model) rememberCommand(name string) error {
+    recent := []string{name}
+    for _, r := range m.paletteRecent {
+        if r != name && len(recent) < maxRecentCommands {
+            recent = append(recent, r)
+        }
+    }
+    m.paletteRecent = recent
+    return saveRecentCommands(recent)
+}
+
+// renderPalette draws the palette into b.
+func (m model) renderPalette(b *strings.Builder, width int) {
+    matches := m.paletteMatches()
+    b.WriteString(styleHeader.Render(fmt.Sprintf("Commands (%d, type to search, ↑/↓ select, Enter run, Esc close)", len(matches))) + "\n")
+    b.WriteString(styleSearch.Render(">"+m.paletteQuery) + styleEditCursor.Render(" ") + "\n")
+    limit := 20
+    if m.height > 8 {
+        limit = m.height - 6
+    }
+    start := 0
+    if m.paletteCursor >= limit {
+        start = m.paletteCursor - limit + 1
+    }
+    recent := make(map[string]bool, len(m.paletteRecent))
+    for _, r := range m.paletteRecent {
+        recent[r] = true
+    }
+    for i := start; i < len(matches) && i < start+limit; i++ {
+        c := matches[i]
+        cursor := "  "
+        if i == m.paletteCursor {
+            cursor = styleCursor.Render("> ")
+        }
+        hint := c.keyHint(m.keys)
+        if recent[c.name] {
+            hint = strings.TrimSpace("recent " + hint)
+        }
+        name := truncateCell(c.name, max(1, width-4-len(hint)))
+        pad := max(1, width-2-visibleWidth(name)-visibleWidth(hint))
+        b.WriteString(cursor + name + strings.Repeat(" ", pad) + styleDim.Render(hint) + "\n")
+    }
+    if len(matches) == 0 {
+        b.WriteString(styleDim.Render("no matching commands") + "\n")
+    }
+}
+
+// keyNamesToTypes maps bubbletea's key names back to their key types.
+var keyNamesToTypes = func() map[string]tea.KeyType {
+    out := make(map[string]tea.KeyType)
+    for t := tea.KeyType(-200); t < 128; t++ {
+        if t == tea.KeyRunes {
+            continue
+        }
+        if s := (tea.Key{Type: t}).String(); s != "" {
+            if _, dup := out[s]; !dup { out[s] = t }
+        }
+    }
+    return out
+}()
+
+// keyMsg builds the key press bubbletea reports as s, e.g. "x", "ctrl+r" or
+// "alt+enter".
+func keyMsg(s string) tea.KeyMsg {
+    alt := false
+    if rest, ok := strings.CutPrefix(s, "alt+"); ok && rest != "" {
+        alt, s = true, rest
+    }
+    if t, ok := keyNamesToTypes[s]; ok {
+        return tea.KeyMsg{Type: t, Alt: alt}
+    }
+    return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s), Alt: alt}
+}
+
+func recentCommandsPath() (string, error) {
+    dir, err := configDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "recent_commands.json"), nil
+}
+
+// loadRecentCommands reads the recently used palette commands, most recent
+// first. A missing file means none.
+func loadRecentCommands() ([]string, error) {
+    path, err := recentCommandsPath()
+    if err != nil {
+        return nil, err
+    }
+    b, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var out []string
+    err = json.Unmarshal(b, &out)
+    return out, err
+}
+
+func saveRecentCommands(names []string) error {
+    path, err := recentCommandsPath()
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    b, err := json.Marshal(names)
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(path, b, 0o600)
+}
+
+// vacuum rebuilds the database file and reports the size change.
+func (m *model) vacuum() {
+    if m.denyWrite("vacuum") {
+        return
+    }
+    if m.tx != nil {
+        m.status = "vacuum can't run while staging: commit (C) or roll back (X) first"
+        return
+    }
+    before := m.databaseSize()
+    if _, err := m.db.Exec("VACUUM"); err != nil {
+        m.status = fmt.Sprintf("vacuum error: %v", err)
+        return
+    }
+    m.status = fmt.Sprintf("vacuumed: %d → %d bytes", before, m.databaseSize())
+}
+
+// databaseSize is the size of the main database in bytes, from its page count.
+func (m model) databaseSize() int64 {
+    var pages, size int64
+    _ = m.conn().QueryRow("PRAGMA page_count").Scan(&pages)
+    _ = m.conn().QueryRow("PRAGMA page_size").Scan(&size)
+    return pages * size
+}
+
+// analyze refreshes the statistics the query planner uses.
+func (m *model) analyze() {
+    if m.denyWrite("analyze") {
+        return
+    }
+    if _, err := m.conn().Exec("ANALYZE"); err != nil {
+        m.status = fmt.Sprintf("analyze error: %v", err)
+        return
+    }
+    m.noteChange()
+    m.status = "analyzed"
+}
+
+// runCheck runs a checking PRAGMA. A clean result is reported in the status
+// line; problems open in the query pane's results grid.
+func (m *model) runCheck(pragma, what string) {
+    res := runQuery(m.conn(), pragma, maxQueryRows)
+    switch {
+    case res.Err != nil:
+        m.status = fmt.Sprintf("%s error: %v", what, res.Err)
+        return
+    case len(res.Rows) == 0, len(res.Rows) == 1 && len(res.Rows[0]) == 1 && res.Rows[0][0] == "ok":
+        m.status = what + ": ok"
+        return
+    }
+    m.queryEditor.setValue(pragma)
+    m.queryResult = &res
+    m.qSelRow, m.qSelCol, m.qColOffset = 0, 0, 0
+    m.queryActive = true
+    m.queryFocusResult = true
+    m.status = fmt.Sprintf("%s: %d problem(s)", what, len(res.Rows))
+}
+
+// toggleReadOnly reopens the database with read-only mode flipped.
+func (m *model) toggleReadOnly() {
+    if m.tx != nil {
+        m.status = "commit (C) or roll back (X) staged changes first"
+        return
+    }
+    opts := m.opts
+    opts.dbPath, opts.readOnly = m.dbPath, !m.readOnly
+    db, err := openDB(opts)
+    if err != nil {
+        m.status = fmt.Sprintf("reopen error: %v", err)
+        return
+    }
+    m.db.Close()
+    m.db = db
+    m.opts = opts
+    m.readOnly = opts.readOnly
+    m.refreshPreview()
+    if m.readOnly {
+        m.status = "read-only on"
+    } else {
+        m.status = "read-only off"
+    }
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+    tests := []struct {
+        pattern, s string
+        ok         bool
+    }{
+        {"", "anything", true},
+        {"exp", "Export table to file", true},
+        {"EXP", "export table to file", true},
+        {"ett", "Export table to file", true},
+        {"xe", "Export", false},
+        {"quitx", "Quit", false},
+        {"é", "Café", true},
+    }
+    for _, tt := range tests {
+        if _, ok := fuzzyMatch(tt.pattern, tt.s); ok != tt.ok {
+            t.Errorf("fuzzyMatch(%q, %q) ok = %v, want %v", tt.pattern, tt.s, ok, tt.ok)
+        }
+    }
+
+    // consecutive and early matches rank higher
+    better := [][3]string{
+        {"undo", "Undo", "Foreign key check: undo"},
+        {"hist", "Query history", "Hex view of cell in string"},
+        {"vac", "Vacuum database", "Move and copy"},
+    }
+    for _, b := range better {
+        hi, _ := fuzzyMatch(b[0], b[1])
+        lo, _ := fuzzyMatch(b[0], b[2])
+        if hi <= lo {
+            t.Errorf("%q: %q scores %d, %q scores %d", b[0], b[1], hi, b[2], lo)
+        }
+    }
+}
+
+func TestKeyMsg(t *testing.T) {
+    for _, k := range []string{"x", "?", "enter", "tab", "esc", "ctrl+r", "ctrl+p", "pgdown", "alt+enter", "alt+d", " "} {
+        if got := keyMsg(k).String(); got != k {
+            t.Errorf("keyMsg(%q).String() = %q", k, got)
+        }
+    }
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// openPrompt starts a one-line input in the status area; kind decides what
+// Enter does with the text (see runPrompt).
+func (m *model) openPrompt(kind, label, initial string) {
+    m.promptActive = true
+    m.promptKind = kind
+    m.promptLabel = label
+    m.promptBuffer = initial
+}
+
+// updatePrompt handles keys while the status-line prompt is open.
+func (m model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    switch msg.Type {
+    case tea.KeyRunes, tea.KeySpace:
+        m.promptBuffer += string(msg.Runes)
+    case tea.KeyBackspace:
+        r := []rune(m.promptBuffer)
+        if len(r) > 0 {
+            m.promptBuffer = string(r[:len(r)-1])
+        }
+    case tea.KeyCtrlU:
+        m.promptBuffer = ""
+    case tea.KeyEnter:
+        m.promptActive = false
+        m.runPrompt(m.promptKind, m.promptBuffer)
+    case tea.KeyEsc, tea.KeyCtrlC:
+        m.promptActive = false
+        m.status = "cancelled"
+    }
+    return m, nil
+}
+
+// runPrompt acts on the submitted prompt text.
+func (m *model) runPrompt(kind, input string) {
+    switch kind {
+    case "export", "export-query":
+        if _, err := os.Stat(input); err == nil {
+            // ask before replacing an existing file
+            m.exportPath, m.exportFromQuery = input, kind == "export-query"
+            m.openPrompt("export-overwrite", input+" exists, overwrite? (y/n)", "")
+            return
+        }
+        m.export(input, kind == "export-query")
+    case "export-overwrite":
+        if a := strings.ToLower(strings.TrimSpace(input)); a != "y" && a != "yes" {
+            m.status = "export cancelled"
+            return
+        }
+        m.export(m.exportPath, m.exportFromQuery)
+    case "json-column":
+        col := m.previewColumns[m.selCol]
+        if err := m.addJSONColumn(col, input); err != nil {
+            m.status = fmt.Sprintf("json column error: %v", err)
+            return
+        }
+        m.status = "added column " + jsonColumnName(col, strings.TrimSpace(input))
+    case "blob-save":
+        if err := m.saveBlob(input); err != nil {
+            m.status = fmt.Sprintf("save error: %v", err)
+            return
+        }
+        m.status = fmt.Sprintf("saved %d bytes to %s", len(m.hexData), input)
+    case "blob-load":
+        if err := m.loadBlob(input); err != nil {
+            m.status = fmt.Sprintf("load error: %v", err)
+            return
+        }
+        m.status = fmt.Sprintf("loaded %d bytes from %s into %s", len(m.hexData), input, m.previewColumns[m.selCol])
+        m.refreshPreview()
+    case "import":
+        // step 1: the file; then ask for the target table
+        if _, err := os.Stat(input); err != nil {
+            m.status = fmt.Sprintf("import error: %v", err)
+            return
+        }
+        m.importPath = input
+        table := m.previewTable
+        if table == "" {
+            base := filepath.Base(input)
+            table = strings.TrimSuffix(base, filepath.Ext(base))
+        }
+        m.openPrompt("import-table", "import into table (existing: append, new: create)", table)
+    case "import-table":
+        if strings.TrimSpace(input) == "" {
+            m.status = "import error: no table name"
+            return
+        }
+        m.importTable = strings.TrimSpace(input)
+        m.openPrompt("import-conflict", "on conflict ("+strings.Join(importConflicts, "/")+")", "abort")
+    case "import-conflict":
+        st, err := m.importFile(m.importPath, m.importTable, strings.ToLower(strings.TrimSpace(input)))
+        if err != nil {
+            m.status = fmt.Sprintf("import error: %v (nothing imported)", err)
+            return
+        }
+        if t, err := listTables(m.conn()); err == nil {
+            sort.Strings(t)
+            m.allTables = t
+            m.applyFilter()
+        }
+        m.selectTable(st.Table)
+        m.status = st.summary()
+    }
+}
+
+// export writes the table or query result to path and reports the outcome.
+func (m *model) export(path string, fromQuery bool) {
+    n, err := m.exportToFile(path, fromQuery)
+    if err != nil {
+        m.status = fmt.Sprintf("export error: %v", err)
+        return
+    }
+    format, _ := formatForPath(path)
+    m.status = fmt.Sprintf("exported %d row(s) to %s (%s%s)", n, path, format, m.exportNote(fromQuery))
+}
+
+// renderPrompt is the status line shown while the prompt is open.
+func (m model) renderPrompt() string {
+    return styleSearch.Render(m.promptLabel+": "+m.promptBuffer) + styleEditCursor.Render(" ")
+}
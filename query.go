@@ -0,0 +1,221 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+// maxQueryRows caps how many result rows an ad-hoc query keeps for display.
+const maxQueryRows = 1000
+
+// queryResult holds the outcome of one ad-hoc statement from the query pane.
+type queryResult struct {
+    SQL       string
+    Columns   []string
+    Rows      [][]string
+    Values    [][]any // the stored values behind Rows, for exports
+    IsSelect  bool
+    Affected  int64
+    Truncated bool // more than maxQueryRows rows were returned
+    Duration  time.Duration
+    Err       error
+}
+
+// rowCount is the number of rows returned (SELECT) or affected (DML).
+func (r queryResult) rowCount() int64 {
+    if r.IsSelect {
+        return int64(len(r.Rows))
+    }
+    return r.Affected
+}
+
+// summary is a one-line description of the result for the status area.
+func (r queryResult) summary() string {
+    if r.Err != nil {
+        return fmt.Sprintf("query error: %v", r.Err)
+    }
+    if r.IsSelect {
+        more := ""
+        if r.Truncated {
+            more = fmt.Sprintf(" (showing first %d)", maxQueryRows)
+        }
+        return fmt.Sprintf("%d row(s)%s in %s", len(r.Rows), more, r.Duration.Round(time.Microsecond))
+    }
+    return fmt.Sprintf("%d row(s) affected in %s", r.Affected, r.Duration.Round(time.Microsecond))
+}
+
+// returnsRows reports whether a statement produces a result set: a query,
+// PRAGMA or EXPLAIN, or DML (possibly behind a WITH clause) with RETURNING.
+func returnsRows(q string) bool {
+    words := sqlWords(q)
+    switch statementVerb(words) {
+    case "SELECT", "VALUES", "PRAGMA", "EXPLAIN":
+        return true
+    }
+    return hasWord(words, "RETURNING")
+}
+
+// sqlWord is a bare word of a statement with its parenthesis depth.
+type sqlWord struct {
+    text  string // upper-cased
+    depth int
+}
+
+// sqlWords splits q into its bare words, skipping string literals, quoted
+// identifiers and comments, so e.g. 'returning customer' is not a keyword.
+func sqlWords(q string) []sqlWord {
+    var out []sqlWord
+    depth := 0
+    isWord := func(c byte) bool {
+        return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+    }
+    skipTo := func(i int, end string) int {
+        if j := strings.Index(q[i:], end); j >= 0 {
+            return i + j + len(end)
+        }
+        return len(q)
+    }
+    for i := 0; i < len(q); {
+        c := q[i]
+        switch {
+        case strings.HasPrefix(q[i:], "--"):
+            i = skipTo(i, "\n")
+        case strings.HasPrefix(q[i:], "/*"):
+            i = skipTo(i+2, "*/")
+        case c == '\'' || c == '"' || c == '`':
+            // a doubled quote inside is just two literals back to back
+            i = skipTo(i+1, string(c))
+        case c == '[':
+            i = skipTo(i+1, "]")
+        case c == '(':
+            depth++
+            i++
+        case c == ')':
+            depth--
+            i++
+        case isWord(c):
+            j := i
+            for j < len(q) && isWord(q[j]) { j++ }
+            out = append(out, sqlWord{strings.ToUpper(q[i:j]), depth})
+            i = j
+        default:
+            i++
+        }
+    }
+    return out
+}
+
+// statementVerb is the keyword that decides what a statement does: its first
+// word, or for WITH the first top-level SELECT, VALUES, INSERT, REPLACE,
+// UPDATE or DELETE after the common table expressions.
+func statementVerb(words []sqlWord) string {
+    if len(words) == 0 {
+        return ""
+    }
+    if words[0].text != "WITH" {
+        return words[0].text
+    }
+    for _, w := range words[1:] {
+        if w.depth != 0 {
+            continue
+        }
+        switch w.text {
+        case "SELECT", "VALUES", "INSERT", "REPLACE", "UPDATE", "DELETE":
+            return w.text
+        }
+    }
+    return "WITH"
+}
+
+func hasWord(words []sqlWord, text string) bool {
+    for _, w := range words {
+        if w.text == text {
+            return true
+        }
+    }
+    return false
+}
+
+// firstKeyword returns the first word of q, skipping whitespace and SQL comments.
+func firstKeyword(q string) string {
+    s := q
+    for {
+        s = strings.TrimSpace(s)
+        switch {
+        case strings.HasPrefix(s, "--"):
+            if i := strings.Index(s, "\n"); i >= 0 { s = s[i+1:] } else { return "" }
+        case strings.HasPrefix(s, "/*"):
+            if i := strings.Index(s, "*/"); i >= 0 { s = s[i+2:] } else { return "" }
+        default:
+            end := strings.IndexFunc(s, func(r rune) bool {
+                return !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'))
+            })
+            if end < 0 { return s }
+            return s[:end]
+        }
+    }
+}
+
+// runQuery executes q against db. Result sets are read into memory up to limit
+// rows; other statements report the number of rows affected.
+func runQuery(db dbConn, q string, limit int) queryResult {
+    res := queryResult{SQL: q}
+    start := time.Now()
+    if strings.TrimSpace(q) == "" {
+        res.Err = fmt.Errorf("empty query")
+        return res
+    }
+    if !returnsRows(q) {
+        r, err := db.Exec(q)
+        if err != nil {
+            res.Err = err
+            res.Duration = time.Since(start)
+            return res
+        }
+        res.Affected, _ = r.RowsAffected()
+        res.Duration = time.Since(start)
+        return res
+    }
+    res.IsSelect = true
+    rows, err := db.Query(q)
+    if err != nil {
+        res.Err = err
+        res.Duration = time.Since(start)
+        return res
+    }
+    defer rows.Close()
+    cols, err := rows.Columns()
+    if err != nil {
+        res.Err = err
+        res.Duration = time.Since(start)
+        return res
+    }
+    res.Columns = cols
+    for rows.Next() {
+        if limit > 0 && len(res.Rows) >= limit {
+            res.Truncated = true
+            break
+        }
+        raw := make([]any, len(cols))
+        dest := make([]any, len(cols))
+        for i := range raw {
+            dest[i] = &raw[i]
+        }
+        if err := rows.Scan(dest...); err != nil {
+            res.Err = err
+            break
+        }
+        rec := make([]string, len(cols))
+        for i, v := range raw {
+            rec[i] = formatValue(v)
+        }
+        res.Rows = append(res.Rows, rec)
+        res.Values = append(res.Values, raw)
+    }
+    if err := rows.Err(); err != nil && res.Err == nil {
+        res.Err = err
+    }
+    res.Duration = time.Since(start)
+    return res
+}
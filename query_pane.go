@@ -0,0 +1,224 @@
+package main
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// queryEditorHeight is the number of editor lines shown in the query pane.
+const queryEditorHeight = 8
+
+// updateQueryPane handles keys while the query pane is open.
+func (m model) updateQueryPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    if m.historyActive {
+        return m.updateHistory(msg)
+    }
+    switch m.actionFor(msg.String(), "Query pane", "Any view") {
+    case "quit":
+        return m.quit()
+    case "history":
+        m.openHistory()
+        return m, nil
+    case "close":
+        m.queryActive = false
+        m.queryFocusResult = false
+        m.status = ""
+        return m, nil
+    case "run":
+        // Ctrl+Enter arrives as ctrl+j (LF) in most terminals
+        m.executeQuery()
+        return m, nil
+    case "focus":
+        if m.queryResult != nil && len(m.queryResult.Columns) > 0 {
+            m.queryFocusResult = !m.queryFocusResult
+        } else {
+            m.queryFocusResult = false
+        }
+        return m, nil
+    }
+    if m.queryFocusResult {
+        res := m.queryResult
+        switch m.actionFor(msg.String(), "Query results") {
+        case "up":
+            if m.qSelRow > 0 { m.qSelRow-- }
+        case "down":
+            if m.qSelRow+1 < len(res.Rows) { m.qSelRow++ }
+        case "left":
+            if m.qSelCol > 0 { m.qSelCol-- }
+        case "right":
+            if m.qSelCol+1 < len(res.Columns) { m.qSelCol++ }
+        case "export":
+            m.openPrompt("export-query", "export result to (.csv .tsv .json .ndjson .md .sql)", "query.csv")
+        case "copy":
+            if m.qSelRow < len(res.Rows) && m.qSelCol < len(res.Rows[m.qSelRow]) {
+                if err := copyToClipboard(res.Rows[m.qSelRow][m.qSelCol]); err != nil {
+                    m.status = fmt.Sprintf("copy error: %v", err)
+                } else {
+                    m.status = "copied"
+                }
+            }
+        }
+        widths := computeColumnWidths(res.Columns, res.Rows)
+        m.qColOffset = scrollOffsetFor(widths, nil, m.qSelCol, m.qColOffset, m.gridWidth())
+        return m, nil
+    }
+    m.queryEditor.handleKey(msg)
+    return m, nil
+}
+
+// executeQuery runs the editor contents and stores the result for display.
+func (m *model) executeQuery() {
+    if m.db == nil {
+        return
+    }
+    res := runQuery(m.conn(), m.queryEditor.value(), maxQueryRows)
+    m.queryResult = &res
+    m.qSelRow, m.qSelCol, m.qColOffset = 0, 0, 0
+    m.queryFocusResult = false
+    m.status = res.summary()
+    if err := appendHistory(newHistoryEntry(m.dbPath, res)); err != nil {
+        m.status += fmt.Sprintf(" (history error: %v)", err)
+    }
+    if res.Err == nil && !res.IsSelect {
+        m.noteChange()
+        // DML/DDL may have changed tables or the previewed rows
+        if t, err := listTables(m.conn()); err == nil {
+            sort.Strings(t)
+            m.allTables = t
+        }
+        m.applyFilter()
+    }
+}
+
+// openHistory loads the saved history and shows the history browser.
+func (m *model) openHistory() {
+    h, err := loadHistory()
+    if err != nil {
+        m.status = fmt.Sprintf("history error: %v", err)
+        return
+    }
+    m.history = h
+    m.historyActive = true
+    m.historyQuery = ""
+    m.historyCursor = 0
+    m.historyMatches = filterHistory(m.history, "")
+}
+
+// updateHistory handles keys in the history browser: typing narrows the list,
+// Enter recalls the selected statement into the editor.
+func (m model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    switch msg.Type {
+    case tea.KeyRunes, tea.KeySpace:
+        m.historyQuery += string(msg.Runes)
+    case tea.KeyBackspace:
+        r := []rune(m.historyQuery)
+        if len(r) == 0 {
+            return m, nil
+        }
+        m.historyQuery = string(r[:len(r)-1])
+    case tea.KeyUp, tea.KeyCtrlP:
+        if m.historyCursor > 0 { m.historyCursor-- }
+        return m, nil
+    case tea.KeyDown, tea.KeyCtrlN:
+        if m.historyCursor+1 < len(m.historyMatches) { m.historyCursor++ }
+        return m, nil
+    case tea.KeyEnter:
+        if m.historyCursor < len(m.historyMatches) {
+            e := m.history[m.historyMatches[m.historyCursor]]
+            m.queryEditor.setValue(e.SQL)
+            m.status = "recalled query from " + e.Time.Local().Format("2006-01-02 15:04")
+        }
+        m.historyActive = false
+        return m, nil
+    case tea.KeyEsc, tea.KeyCtrlC:
+        m.historyActive = false
+        return m, nil
+    default:
+        return m, nil
+    }
+    m.historyMatches = filterHistory(m.history, m.historyQuery)
+    m.historyCursor = 0
+    return m, nil
+}
+
+// renderHistory draws the history browser into b.
+func (m model) renderHistory(b *strings.Builder, width int) {
+    b.WriteString(styleHeader.Render(fmt.Sprintf("History (%d/%d, type to search, ↑/↓ select, Enter recall, Esc back)", len(m.historyMatches), len(m.history))) + "\n")
+    b.WriteString(styleSearch.Render("?"+m.historyQuery) + "\n")
+    limit := 20
+    if m.height > 8 {
+        limit = m.height - 6
+    }
+    start := 0
+    if m.historyCursor >= limit {
+        start = m.historyCursor - limit + 1
+    }
+    for k := start; k < len(m.historyMatches) && k < start+limit; k++ {
+        e := m.history[m.historyMatches[k]]
+        cursor := "  "
+        if k == m.historyCursor {
+            cursor = styleCursor.Render("> ")
+        }
+        meta := fmt.Sprintf("%s %7.1fms %6d ", e.Time.Local().Format("01-02 15:04"), e.DurationMS, e.Rows)
+        if e.Error != "" {
+            meta = fmt.Sprintf("%s %7.1fms %6s ", e.Time.Local().Format("01-02 15:04"), e.DurationMS, "err")
+        }
+        line := truncateCell(oneLine(e.SQL), max(1, width-2-len(meta)))
+        b.WriteString(cursor + styleDim.Render(meta) + line + "\n")
+    }
+    if len(m.historyMatches) == 0 {
+        b.WriteString(styleDim.Render("no matching queries") + "\n")
+    }
+}
+
+// renderQueryPane draws the SQL editor and the latest result into b.
+func (m model) renderQueryPane(b *strings.Builder, width int) {
+    if m.historyActive {
+        m.renderHistory(b, width)
+        return
+    }
+    title := "Query (F5/Ctrl+Enter run, Ctrl+R history, Tab results, Esc close)"
+    if !m.queryFocusResult { title += " " + styleFocusTag.Render("EDIT") }
+    b.WriteString(styleHeader.Render(title) + "\n")
+    lines := m.queryEditor.render(max(1, width-2), queryEditorHeight, !m.queryFocusResult)
+    if len(lines) == 0 {
+        lines = []string{""}
+    }
+    for _, l := range lines {
+        b.WriteString(styleDim.Render("│ ") + l + "\n")
+    }
+    b.WriteString(strings.Repeat("─", max(1, width-2)) + "\n")
+    res := m.queryResult
+    if res == nil {
+        b.WriteString(styleDim.Render("no results yet") + "\n")
+        return
+    }
+    if res.Err != nil {
+        b.WriteString(styleError.Render(res.summary()) + "\n")
+        return
+    }
+    head := "Result: " + res.summary()
+    g := m.queryGrid()
+    // title, editor, rule, result line and grid header are above the rows
+    g.maxRows = m.gridRows(5 + len(lines))
+    if span := rowSpan(g); span != "" { head += " " + span }
+    if span := columnSpan(g); span != "" { head += " " + span }
+    if m.queryFocusResult { head += " " + styleFocusTag.Render("FOCUS") }
+    b.WriteString(styleHeader.Render(head) + "\n")
+    if len(res.Columns) > 0 {
+        renderGrid(b, g)
+    }
+}
+
+// queryGrid describes the latest query result for renderGrid.
+func (m model) queryGrid() gridSpec {
+    g := gridSpec{width: m.gridWidth(), offset: m.qColOffset, focused: m.queryFocusResult, selRow: m.qSelRow, selCol: m.qSelCol}
+    if m.queryResult != nil {
+        g.cols = m.queryResult.Columns
+        g.rows = m.queryResult.Rows
+    }
+    return g
+}
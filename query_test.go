@@ -0,0 +1,55 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestSQLWords(t *testing.T) {
+    tests := []struct {
+        q    string
+        want []sqlWord
+    }{
+        {"select a from t", []sqlWord{{"SELECT", 0}, {"A", 0}, {"FROM", 0}, {"T", 0}}},
+        {"SELECT 'it''s' , \"col x\", [y], `z` -- tail\n/* c */ x", []sqlWord{{"SELECT", 0}, {"X", 0}}},
+        {"WITH c AS (SELECT 1) DELETE", []sqlWord{{"WITH", 0}, {"C", 0}, {"AS", 0}, {"SELECT", 1}, {"1", 1}, {"DELETE", 0}}},
+        {"-- only a comment", nil},
+        {"SELECT 'unterminated", []sqlWord{{"SELECT", 0}}},
+    }
+    for _, tt := range tests {
+        if got := sqlWords(tt.q); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("sqlWords(%q) = %v, want %v", tt.q, got, tt.want)
+        }
+    }
+}
+
+func TestReturnsRows(t *testing.T) {
+    tests := []struct {
+        q     string
+        rows  bool
+        rerun bool
+    }{
+        {"SELECT * FROM t", true, true},
+        {"  -- note\n values (1)", true, true},
+        {"WITH c AS (SELECT 1) SELECT * FROM c", true, true},
+        {"PRAGMA table_info(t)", true, false},
+        {"EXPLAIN QUERY PLAN SELECT 1", true, false},
+        {"DELETE FROM t RETURNING id", true, false},
+        {"WITH c AS (SELECT 1) DELETE FROM t WHERE id IN c", false, false},
+        {"WITH c AS (SELECT 1) UPDATE t SET v = 1 RETURNING *", true, false},
+        // RETURNING inside literals, identifiers and longer words is not the keyword
+        {"UPDATE t SET note = 'returning customer'", false, false},
+        {"INSERT INTO \"returning\" VALUES (1)", false, false},
+        {"UPDATE t SET returning_date = 1", false, false},
+        {"SELECT 'returning'", true, true},
+        {"CREATE TABLE x(a)", false, false},
+    }
+    for _, tt := range tests {
+        if got := returnsRows(tt.q); got != tt.rows {
+            t.Errorf("returnsRows(%q) = %v, want %v", tt.q, got, tt.rows)
+        }
+        if got := rerunnable(tt.q); got != tt.rerun {
+            t.Errorf("rerunnable(%q) = %v, want %v", tt.q, got, tt.rerun)
+        }
+    }
+}
@@ -0,0 +1,21 @@
+package main
+
+import "fmt"
+
+// denyWrite reports whether a write action must be refused because the
+// database is open read-only, and says so in the status line.
+func (m *model) denyWrite(what string) bool {
+    if !m.readOnly {
+        return false
+    }
+    m.status = fmt.Sprintf("read-only: %s is disabled", what)
+    return true
+}
+
+// readOnlyBadge is the header indicator shown when the database is read-only.
+func (m model) readOnlyBadge() string {
+    if !m.readOnly {
+        return ""
+    }
+    return styleReadOnly.Render(" READ-ONLY ")
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// tableSchema is everything the schema tab shows for one table or view.
+type tableSchema struct {
+    Table    string
+    Type     string
+    DDL      string
+    Indexes  []indexInfo
+    Triggers []triggerInfo
+    Outgoing []foreignKey
+    Incoming []foreignKey
+}
+
+// loadTableSchema reads the DDL, indexes, triggers and foreign keys of table.
+func loadTableSchema(db dbConn, table string) (*tableSchema, error) {
+    s := &tableSchema{Table: table}
+    var err error
+    if s.Type, err = getObjectType(db, table); err != nil {
+        return nil, err
+    }
+    if s.DDL, err = getCreateSQL(db, table); err != nil {
+        return nil, err
+    }
+    if s.Indexes, err = getIndexes(db, table); err != nil {
+        return nil, err
+    }
+    if s.Triggers, err = getTriggers(db, table); err != nil {
+        return nil, err
+    }
+    if s.Outgoing, err = getForeignKeys(db, table); err != nil {
+        return nil, err
+    }
+    if s.Incoming, err = getIncomingForeignKeys(db, table); err != nil {
+        return nil, err
+    }
+    return s, nil
+}
+
+// loadSchema refreshes the schema tab for the table under the cursor.
+func (m *model) loadSchema() {
+    m.schema = nil
+    m.schemaScroll = 0
+    if m.db == nil || m.cursor < 0 || m.cursor >= len(m.tables) {
+        return
+    }
+    s, err := loadTableSchema(m.conn(), m.tables[m.cursor])
+    if err != nil {
+        m.status = fmt.Sprintf("schema error: %v", err)
+        return
+    }
+    m.schema = s
+}
+
+// updateSchema handles keys while the schema tab has focus.
+func (m model) updateSchema(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+    lines := m.schemaLines(m.gridWidth())
+    h := m.detailHeight() - 1
+    maxScroll := max(0, len(lines)-h)
+    switch m.actionFor(msg.String(), "Schema tab", "Any view") {
+    case "quit":
+        return m.quit()
+    case "schema":
+        m.schemaTab = false
+        m.refreshPreview()
+    case "back":
+        m.focusPreview = false
+    case "up":
+        if m.schemaScroll > 0 { m.schemaScroll-- }
+    case "down":
+        if m.schemaScroll < maxScroll { m.schemaScroll++ }
+    case "page-up":
+        m.schemaScroll = max(0, m.schemaScroll-max(1, h/2))
+    case "page-down":
+        m.schemaScroll = min(maxScroll, m.schemaScroll+max(1, h/2))
+    case "top":
+        m.schemaScroll = 0
+    case "bottom":
+        m.schemaScroll = maxScroll
+    }
+    return m, nil
+}
+
+// describeFK formats a foreign key as "child(a, b) → parent(x, y)".
+func describeFK(fk foreignKey) string {
+    to := make([]string, len(fk.To))
+    for i, c := range fk.To {
+        if c == "" { c = "<pk>" }
+        to[i] = c
+    }
+    s := fmt.Sprintf("%s(%s) → %s(%s)", fk.Table, strings.Join(fk.From, ", "), fk.RefTable, strings.Join(to, ", "))
+    if fk.OnDelete != "" && !strings.EqualFold(fk.OnDelete, "NO ACTION") {
+        s += " ON DELETE " + fk.OnDelete
+    }
+    if fk.OnUpdate != "" && !strings.EqualFold(fk.OnUpdate, "NO ACTION") {
+        s += " ON UPDATE " + fk.OnUpdate
+    }
+    return s
+}
+
+// schemaLines lays out the schema tab as wrapped display lines.
+func (m model) schemaLines(width int) []string {
+    s := m.schema
+    if s == nil {
+        return []string{styleDim.Render("no schema loaded")}
+    }
+    var lines []string
+    // section starts a titled block; n < 0 omits the item count
+    section := func(title string, n int) {
+        if len(lines) > 0 {
+            lines = append(lines, "")
+        }
+        if n >= 0 {
+            title = fmt.Sprintf("%s (%d)", title, n)
+        }
+        lines = append(lines, "  "+styleColSelect.Render(title))
+    }
+    // lines already carry the pane's 2-char gutter; items and wrapped text
+    // are indented beneath their section title
+    indent := func(text string) {
+        for _, w := range wrapText(text, max(1, width-6)) {
+            lines = append(lines, "      "+w)
+        }
+    }
+    section("DDL", -1)
+    if s.DDL == "" {
+        indent("(no CREATE statement recorded)")
+    } else {
+        indent(s.DDL)
+    }
+    section("Indexes", len(s.Indexes))
+    for _, ix := range s.Indexes {
+        cols := make([]string, len(ix.Columns))
+        for i, c := range ix.Columns {
+            if c == "" { c = "<expr>" }
+            cols[i] = c
+        }
+        var flags []string
+        if ix.Unique { flags = append(flags, "UNIQUE") }
+        if ix.Partial { flags = append(flags, "PARTIAL") }
+        switch ix.Origin {
+        case "pk":
+            flags = append(flags, "primary key")
+        case "u":
+            flags = append(flags, "from UNIQUE constraint")
+        }
+        line := fmt.Sprintf("%s (%s)", ix.Name, strings.Join(cols, ", "))
+        if len(flags) > 0 {
+            line += " " + styleDim.Render(strings.Join(flags, ", "))
+        }
+        lines = append(lines, "    "+line)
+        if ix.Partial && ix.SQL != "" {
+            indent(ix.SQL)
+        }
+    }
+    section("Triggers", len(s.Triggers))
+    for _, t := range s.Triggers {
+        lines = append(lines, "    "+t.Name)
+        indent(t.SQL)
+    }
+    section("Foreign keys (outgoing)", len(s.Outgoing))
+    for _, fk := range s.Outgoing {
+        lines = append(lines, "    "+describeFK(fk))
+    }
+    section("Foreign keys (incoming)", len(s.Incoming))
+    for _, fk := range s.Incoming {
+        lines = append(lines, "    "+describeFK(fk))
+    }
+    return lines
+}
+
+// renderSchema draws the schema tab into b.
+func (m model) renderSchema(b *strings.Builder, width int) {
+    title := fmt.Sprintf("%s  %s", styleDim.Render("Data"), styleFocusTag.Render("Schema"))
+    if m.schema != nil {
+        title += " " + styleHeader.Render(fmt.Sprintf("%s %s", m.schema.Type, m.schema.Table))
+    }
+    if m.focusPreview { title += " " + styleFocusTag.Render("FOCUS") }
+    b.WriteString(title + "\n")
+    lines := m.schemaLines(width)
+    h := m.detailHeight() - 1
+    start := min(m.schemaScroll, max(0, len(lines)-1))
+    for i := start; i < len(lines) && i < start+h; i++ {
+        b.WriteString(lines[i] + "\n")
+    }
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagedCell identifies an edited cell: table, row key and column.
+type stagedCell struct {
+    table string
+    row   string
+    col   string
+}
+
+// beginStaging opens the transaction that collects edits until commit or rollback.
+func (m *model) beginStaging() error {
+    if m.db == nil {
+        return fmt.Errorf("db not open")
+    }
+    if m.tx != nil {
+        return nil
+    }
+    tx, err := m.db.Begin()
+    if err != nil {
+        return err
+    }
+    m.tx = tx
+    m.pending = 0
+    m.stagedCells = make(map[stagedCell]bool)
+    m.undoMark = len(m.undoStack)
+    return nil
+}
+
+// commitStaging commits the staged edits and, unless stop is set, opens a
+// fresh transaction so staging mode continues.
+func (m *model) commitStaging(stop bool) error {
+    if m.tx == nil {
+        return fmt.Errorf("staging mode is off")
+    }
+    err := m.tx.Commit()
+    m.tx = nil
+    m.pending = 0
+    m.stagedCells = nil
+    if err != nil || stop {
+        return err
+    }
+    return m.beginStaging()
+}
+
+// rollbackStaging discards the staged edits, keeping staging mode on unless stop is set.
+func (m *model) rollbackStaging(stop bool) error {
+    if m.tx == nil {
+        return fmt.Errorf("staging mode is off")
+    }
+    err := m.tx.Rollback()
+    m.tx = nil
+    m.pending = 0
+    m.stagedCells = nil
+    // the rolled back edits can no longer be undone or redone
+    m.dropUndoSince(m.undoMark)
+    if err != nil || stop {
+        return err
+    }
+    return m.beginStaging()
+}
+
+// atomic runs fn as one unit: inside a savepoint called name while staging,
+// otherwise in a transaction of its own. Nothing fn did survives an error.
+func (m *model) atomic(name string, fn func(conn dbConn) error) error {
+    var conn dbConn
+    var finish func(ok bool) error
+    if m.tx != nil {
+        if _, err := m.tx.Exec("SAVEPOINT " + name); err != nil {
+            return err
+        }
+        conn = m.tx
+        finish = func(ok bool) error {
+            if !ok {
+                if _, err := m.tx.Exec("ROLLBACK TO " + name); err != nil {
+                    return err
+                }
+            }
+            _, err := m.tx.Exec("RELEASE " + name)
+            return err
+        }
+    } else {
+        tx, err := m.db.Begin()
+        if err != nil {
+            return err
+        }
+        conn = tx
+        finish = func(ok bool) error {
+            if !ok {
+                return tx.Rollback()
+            }
+            return tx.Commit()
+        }
+    }
+    if err := fn(conn); err != nil {
+        if ferr := finish(false); ferr != nil {
+            return fmt.Errorf("%v (rollback: %v)", err, ferr)
+        }
+        return err
+    }
+    return finish(true)
+}
+
+// noteChange counts a successful mutation made inside the staging transaction.
+func (m *model) noteChange() {
+    if m.tx != nil {
+        m.pending++
+    }
+}
+
+// markCellChanged records an edited cell so the grid can highlight it.
+func (m *model) markCellChanged(ri int, col string) {
+    if m.tx == nil {
+        return
+    }
+    if id, ok := m.rowIdentity(ri); ok {
+        m.stagedCells[stagedCell{table: m.previewTable, row: id, col: strings.ToLower(col)}] = true
+    }
+}
+
+// rowIdentity returns a stable key for preview row ri built from its PK or rowid.
+func (m model) rowIdentity(ri int) (string, bool) {
+    if ri < 0 || ri >= len(m.previewKeys) || len(m.keyCols) == 0 {
+        return "", false
+    }
+    vals := m.previewKeys[ri]
+    vals = vals[len(vals)-len(m.keyCols):] // skip the sort value, if any
+    parts := make([]string, len(vals))
+    for i, v := range vals {
+        parts[i] = formatValue(v)
+    }
+    return strings.Join(parts, "\x1f"), true
+}
+
+// stagedGridCells returns the (row, column) positions of staged edits on the current page.
+func (m model) stagedGridCells() map[[2]int]bool {
+    if len(m.stagedCells) == 0 {
+        return nil
+    }
+    out := make(map[[2]int]bool)
+    for ri := range m.preview {
+        id, ok := m.rowIdentity(ri)
+        if !ok {
+            continue
+        }
+        for ci, c := range m.previewColumns {
+            if m.stagedCells[stagedCell{table: m.previewTable, row: id, col: strings.ToLower(c)}] {
+                out[[2]int{ri, ci}] = true
+            }
+        }
+    }
+    return out
+}
+
+// quit leaves the program, asking first when staged changes would be lost.
+func (m model) quit() (tea.Model, tea.Cmd) {
+    if m.tx != nil && m.pending > 0 && !m.confirmQuitActive {
+        m.confirmQuitActive = true
+        m.status = fmt.Sprintf("%d uncommitted change(s) will be rolled back. Quit anyway? (y/n)", m.pending)
+        return m, nil
+    }
+    if m.tx != nil {
+        _ = m.tx.Rollback()
+        m.tx = nil
+    }
+    if m.db != nil {
+        _ = m.db.Close()
+    }
+    return m, tea.Quit
+}
+
+// stagingBadge is the status-line indicator shown while staging is on.
+func (m model) stagingBadge() string {
+    if m.tx == nil {
+        return ""
+    }
+    return styleStaging.Render(fmt.Sprintf(" STAGING %d pending ", m.pending))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
     "regexp"
+    "sort"
     "strings"
     "unicode/utf8"
 
@@ -9,16 +10,140 @@ import (
 )
 
 var (
-    styleHeader    = lipgloss.NewStyle().Foreground(lipgloss.Color("63")).Bold(true)
-    stylePrompt    = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
-    styleSearch    = lipgloss.NewStyle().Foreground(lipgloss.Color("45"))
-    styleCursor    = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
-    styleFocusTag  = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Bold(true)
-    styleError     = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
-    styleInfo      = lipgloss.NewStyle().Foreground(lipgloss.Color("178"))
-    styleColSelect = lipgloss.NewStyle().Foreground(lipgloss.Color("213")).Bold(true)
+    styleHeader     lipgloss.Style
+    stylePrompt     lipgloss.Style
+    styleSearch     lipgloss.Style
+    styleCursor     lipgloss.Style
+    styleFocusTag   lipgloss.Style
+    styleError      lipgloss.Style
+    styleInfo       lipgloss.Style
+    styleColSelect  lipgloss.Style
+    styleEditCursor = lipgloss.NewStyle().Reverse(true)
+    styleDim        lipgloss.Style
+    styleStaging    lipgloss.Style
+    styleChanged    lipgloss.Style
+    styleChip       lipgloss.Style
+    styleJSONKey     lipgloss.Style
+    styleJSONString  lipgloss.Style
+    styleJSONNumber  lipgloss.Style
+    styleJSONLiteral lipgloss.Style
+    styleReadOnly   lipgloss.Style
 )
 
+// theme maps the color slots the styles are built from to lipgloss colors
+// (ANSI 256 numbers like "63" or hex like "#5f5fff").
+type theme map[string]string
+
+// themes are the built-in color themes; "default" is used unless --theme
+// picks another.
+var themes = map[string]theme{
+    "default": {
+        "header": "63", "prompt": "196", "search": "45", "cursor": "212",
+        "focus_fg": "229", "focus_bg": "57", "error": "196", "info": "178",
+        "col_select": "213", "dim": "241", "staging_fg": "16", "staging_bg": "214",
+        "changed_fg": "16", "changed_bg": "221", "chip_fg": "230", "chip_bg": "24",
+        "json_key": "75", "json_string": "114", "json_number": "179", "json_literal": "176",
+        "read_only_fg": "231", "read_only_bg": "124",
+    },
+    "light": {
+        "header": "25", "prompt": "160", "search": "31", "cursor": "162",
+        "focus_fg": "231", "focus_bg": "25", "error": "160", "info": "130",
+        "col_select": "127", "dim": "245", "staging_fg": "16", "staging_bg": "214",
+        "changed_fg": "16", "changed_bg": "222", "chip_fg": "16", "chip_bg": "153",
+        "json_key": "25", "json_string": "28", "json_number": "130", "json_literal": "90",
+        "read_only_fg": "231", "read_only_bg": "124",
+    },
+    "mono": {
+        "header": "255", "prompt": "255", "search": "250", "cursor": "255",
+        "focus_fg": "16", "focus_bg": "252", "error": "255", "info": "250",
+        "col_select": "255", "dim": "244", "staging_fg": "16", "staging_bg": "250",
+        "changed_fg": "16", "changed_bg": "247", "chip_fg": "255", "chip_bg": "238",
+        "json_key": "252", "json_string": "250", "json_number": "250", "json_literal": "250",
+        "read_only_fg": "16", "read_only_bg": "255",
+    },
+}
+
+func themeNames() []string { return sortedKeys(themes) }
+
+var themeColorRe = regexp.MustCompile(`^([0-9]{1,3}|#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6})$`)
+
+// loadThemes adds the [themes.<name>] sections of the config file to themes.
+// A theme starts from the colors of its base (default unless base = "...")
+// and overrides the slots it sets, e.g. header = "#5f87ff".
+func loadThemes(cfg *config) error {
+    for _, sec := range cfg.order {
+        name, ok := strings.CutPrefix(sec, "themes.")
+        if !ok {
+            continue
+        }
+        if strings.Contains(name, ".") {
+            return cfg.errorf(cfg.sectionLines[sec], "bad theme name %q", name)
+        }
+        vals := cfg.sections[sec]
+        baseName := "default"
+        if b, ok := vals["base"]; ok {
+            baseName = b.raw
+        }
+        base, ok := themes[baseName]
+        if !ok {
+            return cfg.errorf(vals["base"].line, "theme %s: unknown base theme %q (have %s)", name, baseName, strings.Join(themeNames(), ", "))
+        }
+        t := make(theme, len(base))
+        for slot, c := range base {
+            t[slot] = c
+        }
+        for _, slot := range sortedKeys(vals) {
+            v := vals[slot]
+            if slot == "base" {
+                continue
+            }
+            if _, known := base[slot]; !known {
+                return cfg.errorf(v.line, "theme %s: unknown color %q (have %s)", name, slot, strings.Join(sortedKeys(base), ", "))
+            }
+            if !themeColorRe.MatchString(v.raw) || (v.raw[0] != '#' && len(v.raw) == 3 && v.raw > "255") {
+                return cfg.errorf(v.line, "theme %s: %s: %q is not a color (use 0-255 or #rrggbb)", name, slot, v.raw)
+            }
+            t[slot] = v.raw
+        }
+        themes[name] = t
+    }
+    return nil
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+    out := make([]string, 0, len(m))
+    for k := range m {
+        out = append(out, k)
+    }
+    sort.Strings(out)
+    return out
+}
+
+// applyTheme rebuilds the styles from t.
+func applyTheme(t theme) {
+    c := func(slot string) lipgloss.Color { return lipgloss.Color(t[slot]) }
+    styleHeader = lipgloss.NewStyle().Foreground(c("header")).Bold(true)
+    stylePrompt = lipgloss.NewStyle().Foreground(c("prompt")).Bold(true)
+    styleSearch = lipgloss.NewStyle().Foreground(c("search"))
+    styleCursor = lipgloss.NewStyle().Foreground(c("cursor")).Bold(true)
+    styleFocusTag = lipgloss.NewStyle().Foreground(c("focus_fg")).Background(c("focus_bg")).Bold(true)
+    styleError = lipgloss.NewStyle().Foreground(c("error")).Bold(true)
+    styleInfo = lipgloss.NewStyle().Foreground(c("info"))
+    styleColSelect = lipgloss.NewStyle().Foreground(c("col_select")).Bold(true)
+    styleDim = lipgloss.NewStyle().Foreground(c("dim"))
+    styleStaging = lipgloss.NewStyle().Foreground(c("staging_fg")).Background(c("staging_bg")).Bold(true)
+    styleChanged = lipgloss.NewStyle().Foreground(c("changed_fg")).Background(c("changed_bg"))
+    styleChip = lipgloss.NewStyle().Foreground(c("chip_fg")).Background(c("chip_bg"))
+    styleJSONKey = lipgloss.NewStyle().Foreground(c("json_key"))
+    styleJSONString = lipgloss.NewStyle().Foreground(c("json_string"))
+    styleJSONNumber = lipgloss.NewStyle().Foreground(c("json_number"))
+    styleJSONLiteral = lipgloss.NewStyle().Foreground(c("json_literal"))
+    styleReadOnly = lipgloss.NewStyle().Foreground(c("read_only_fg")).Background(c("read_only_bg")).Bold(true)
+}
+
+func init() { applyTheme(themes["default"]) }
+
 // ansiRegexp matches ANSI SGR escape sequences for styling (e.g., "\x1b[31m").
 var ansiRegexp = regexp.MustCompile("\x1b\\[[0-9;]*m")
 
@@ -0,0 +1,142 @@
+package main
+
+import (
+    "database/sql"
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// trashPrefix names the per-table trash tables that hold soft-deleted rows,
+// e.g. deleting from "users" copies the row into "_trash_users".
+const trashPrefix = "_trash_"
+
+// Trash tables carry two bookkeeping columns ahead of the source columns.
+const (
+    trashDeletedAt = "_deleted_at"
+    trashSrcRowid  = "_src_rowid"
+)
+
+func trashTableName(table string) string { return trashPrefix + table }
+
+func isTrashTable(table string) bool { return strings.HasPrefix(table, trashPrefix) }
+
+// ensureTrashTable creates the trash table for table if it doesn't exist yet,
+// mirroring the source columns and their declared types without constraints.
+func ensureTrashTable(db dbConn, table string, cols []colInfo) error {
+    defs := []string{quoteIdent(trashDeletedAt) + " TEXT", quoteIdent(trashSrcRowid) + " INTEGER"}
+    for _, c := range cols {
+        defs = append(defs, strings.TrimSpace(quoteIdent(c.Name)+" "+c.Type))
+    }
+    q := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteIdent(trashTableName(table)), strings.Join(defs, ", "))
+    _, err := db.Exec(q)
+    return err
+}
+
+// copyToTrash copies the located row into the table's trash table and returns
+// the trash row's rowid.
+func (m *model) copyToTrash(table string, loc rowLocator) (int64, error) {
+    // undo can replay deletes on a table other than the one shown
+    cols, err := getTableInfo(m.conn(), table)
+    if err != nil {
+        return 0, err
+    }
+    if err := ensureTrashTable(m.conn(), table, cols); err != nil {
+        return 0, err
+    }
+    names := make([]string, len(cols))
+    for i, c := range cols {
+        names[i] = c.Name
+    }
+    srcRowid := "NULL"
+    if hasRowid(m.conn(), table) {
+        srcRowid = "rowid"
+    }
+    where, params := loc.where()
+    q := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) SELECT datetime('now'), %s, %s FROM %s WHERE %s",
+        quoteIdent(trashTableName(table)), quoteIdent(trashDeletedAt), quoteIdent(trashSrcRowid), quoteIdentList(names),
+        srcRowid, quoteIdentList(names), quoteIdent(table), where)
+    res, err := m.conn().Exec(q, params...)
+    if err != nil {
+        return 0, err
+    }
+    return res.LastInsertId()
+}
+
+// dropFromTrash removes the trash copy with rowid id of a row from table.
+func (m *model) dropFromTrash(table string, id int64) error {
+    _, err := m.conn().Exec(fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", quoteIdent(trashTableName(table))), id)
+    return err
+}
+
+// hasRowid reports whether table is an ordinary rowid table (not WITHOUT ROWID).
+func hasRowid(db dbConn, table string) bool {
+    var n any
+    err := db.QueryRow(fmt.Sprintf("SELECT rowid FROM %s LIMIT 1", quoteIdent(table))).Scan(&n)
+    return err == nil || errors.Is(err, sql.ErrNoRows)
+}
+
+// restoreFromTrash moves the selected row of a trash table back into its
+// source table, keeping the original rowid when there was one.
+func (m *model) restoreFromTrash() (string, error) {
+    trash := m.previewTable
+    if !isTrashTable(trash) {
+        return "", fmt.Errorf("%s is not a trash table", trash)
+    }
+    src := strings.TrimPrefix(trash, trashPrefix)
+    if m.previewRowIDs == nil || m.selRow >= len(m.previewRowIDs) {
+        return "", fmt.Errorf("no row selected")
+    }
+    trashRowid := m.previewRowIDs[m.selRow]
+    srcCols, err := getTableInfo(m.conn(), src)
+    if err != nil {
+        return "", err
+    }
+    if len(srcCols) == 0 {
+        return "", fmt.Errorf("source table %s no longer exists", src)
+    }
+    // only copy columns both tables still have
+    var names []string
+    have := m.tableColumns()
+    for _, c := range srcCols {
+        if findColIndex(have, c.Name) >= 0 {
+            names = append(names, c.Name)
+        }
+    }
+    target := quoteIdentList(names)
+    source := quoteIdentList(names)
+    if hasRowid(m.conn(), src) {
+        target = "rowid, " + target
+        source = fmt.Sprintf("COALESCE(%s, (SELECT COALESCE(MAX(rowid), 0) + 1 FROM %s)), %s", quoteIdent(trashSrcRowid), quoteIdent(src), source)
+    }
+    q := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE rowid = ?", quoteIdent(src), target, source, quoteIdent(trash))
+    // move the row in one step so it never ends up in both tables
+    err = m.atomic("restore", func(conn dbConn) error {
+        if _, err := conn.Exec(q, trashRowid); err != nil {
+            return err
+        }
+        _, err := conn.Exec(fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", quoteIdent(trash)), trashRowid)
+        return err
+    })
+    if err != nil {
+        return "", err
+    }
+    m.noteChange()
+    return src, nil
+}
+
+// rowLabel describes preview row ri by its key for prompts, e.g. "id=5" or "rowid 12".
+func (m model) rowLabel(ri int) string {
+    loc, ok := m.rowLocator(ri)
+    if !ok {
+        return fmt.Sprintf("row %d", m.pageOffset+ri+1)
+    }
+    if len(loc.cols) == 1 && loc.cols[0] == "rowid" {
+        return fmt.Sprintf("rowid %s", formatValue(loc.vals[0]))
+    }
+    parts := make([]string, len(loc.cols))
+    for i, c := range loc.cols {
+        parts[i] = fmt.Sprintf("%s=%s", c, formatValue(loc.vals[i]))
+    }
+    return strings.Join(parts, ", ")
+}
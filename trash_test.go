@@ -0,0 +1,105 @@
+package main
+
+import (
+    "fmt"
+    "sort"
+    "testing"
+)
+
+func countRows(t *testing.T, m *model, table string) int {
+    t.Helper()
+    var n int
+    if err := m.conn().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdent(table))).Scan(&n); err != nil {
+        t.Fatal(err)
+    }
+    return n
+}
+
+// openTable reloads the table list and selects table in the preview.
+func openTable(t *testing.T, m *model, table string) {
+    t.Helper()
+    tables, err := listTables(m.conn())
+    if err != nil {
+        t.Fatal(err)
+    }
+    sort.Strings(tables)
+    m.allTables = tables
+    m.applyFilter()
+    if !m.selectTable(table) {
+        t.Fatalf("no table %s in %v", table, tables)
+    }
+}
+
+func TestHasRowid(t *testing.T) {
+    m := newTestModel(t, options{},
+        "CREATE TABLE empty(a)",
+        "CREATE TABLE full(a)", "INSERT INTO full VALUES (1)",
+        "CREATE TABLE wr(a PRIMARY KEY) WITHOUT ROWID",
+        "CREATE VIEW v AS SELECT 1 AS a")
+    tests := map[string]bool{"empty": true, "full": true, "wr": false, "missing": false}
+    for table, want := range tests {
+        if got := hasRowid(m.conn(), table); got != want {
+            t.Errorf("hasRowid(%s) = %v, want %v", table, got, want)
+        }
+    }
+}
+
+func TestSoftDeleteUndoRedo(t *testing.T) {
+    m := newTestModel(t, options{table: "t", trash: true},
+        "CREATE TABLE t(id INTEGER PRIMARY KEY, v TEXT)",
+        "INSERT INTO t VALUES (1, 'a'), (2, 'b')")
+    if err := m.deleteCurrentRow(); err != nil {
+        t.Fatal(err)
+    }
+    trash := trashTableName("t")
+    steps := []struct {
+        name       string
+        run        func() (string, error)
+        rows, kept int
+    }{
+        {"undo", m.undo, 2, 0},
+        {"redo", m.redo, 1, 1},
+        {"undo again", m.undo, 2, 0},
+    }
+    if got, kept := countRows(t, &m, "t"), countRows(t, &m, trash); got != 1 || kept != 1 {
+        t.Fatalf("after delete: %d row(s), %d in trash", got, kept)
+    }
+    for _, s := range steps {
+        if _, err := s.run(); err != nil {
+            t.Fatalf("%s: %v", s.name, err)
+        }
+        if got, kept := countRows(t, &m, "t"), countRows(t, &m, trash); got != s.rows || kept != s.kept {
+            t.Errorf("after %s: %d row(s), %d in trash, want %d and %d", s.name, got, kept, s.rows, s.kept)
+        }
+    }
+}
+
+func TestRestoreFromTrashIsAtomic(t *testing.T) {
+    for _, staged := range []bool{false, true} {
+        t.Run(fmt.Sprintf("staged=%v", staged), func(t *testing.T) {
+            m := newTestModel(t, options{table: "t", trash: true},
+                "CREATE TABLE t(id INTEGER PRIMARY KEY, v TEXT)",
+                "INSERT INTO t VALUES (1, 'a')")
+            if err := m.deleteCurrentRow(); err != nil {
+                t.Fatal(err)
+            }
+            // the row can be copied back, but not removed from the trash
+            trash := trashTableName("t")
+            if _, err := m.conn().Exec(fmt.Sprintf("CREATE TRIGGER keep BEFORE DELETE ON %s BEGIN SELECT RAISE(ABORT, 'kept'); END", quoteIdent(trash))); err != nil {
+                t.Fatal(err)
+            }
+            if staged {
+                if err := m.beginStaging(); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            openTable(t, &m, trash)
+            if _, err := m.restoreFromTrash(); err == nil {
+                t.Fatal("restore succeeded despite the trigger")
+            }
+            if got, kept := countRows(t, &m, "t"), countRows(t, &m, trash); got != 0 || kept != 1 {
+                t.Errorf("after a failed restore: %d row(s), %d in trash, want 0 and 1", got, kept)
+            }
+        })
+    }
+}
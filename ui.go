@@ -1,6 +1,7 @@
 package main
 
 import (
+    "errors"
     "fmt"
     "sort"
     "strings"
@@ -24,7 +25,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
     switch msg := msg.(type) {
     case tablesTickMsg:
         if m.db != nil {
-            if t, err := listTables(m.db); err == nil {
+            if t, err := listTables(m.conn()); err == nil {
                 sort.Strings(t)
                 if !equalStrings(t, m.allTables) {
                     m.allTables = t
@@ -36,38 +37,63 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
             }
         }
         return m, tablesRefreshCmd()
+    case editorDoneMsg:
+        m.finishExternalEdit(msg)
+        return m, nil
     case tea.KeyMsg:
         // If currently editing a cell, handle input differently
         if m.editingActive {
-            switch msg.Type {
-            case tea.KeyRunes:
-                if len(msg.Runes) > 0 {
-                    m.editBuffer += string(msg.Runes)
-                }
-                return m, nil
-            case tea.KeyBackspace:
-                r := []rune(m.editBuffer)
-                if len(r) > 0 {
-                    m.editBuffer = string(r[:len(r)-1])
+            switch {
+            case msg.Type == tea.KeyEnter && !msg.Alt && !msg.Paste, msg.Type == tea.KeyCtrlS:
+                // commit edit (Alt+Enter inserts a newline instead)
+                err := m.commitCellEdit()
+                var verr validationError
+                if errors.As(err, &verr) {
+                    // bad value: keep editing, nothing was written
+                    m.editErr = verr.msg
+                    m.status = fmt.Sprintf("invalid value: %v", verr)
+                    return m, nil
                 }
-                return m, nil
-            case tea.KeyEnter:
-                // commit edit
-                if err := m.commitCellEdit(); err != nil {
+                if err != nil {
                     m.status = fmt.Sprintf("update error: %v", err)
                 } else {
                     m.status = "updated"
                 }
                 m.editingActive = false
-                m.editBuffer = ""
+                m.editErr = ""
+                m.cellEditor.setValue("")
                 m.refreshPreview()
                 return m, nil
-            case tea.KeyEsc:
+            case msg.Type == tea.KeyEsc:
                 m.editingActive = false
-                m.editBuffer = ""
+                m.editErr = ""
+                m.cellEditor.setValue("")
                 m.status = "cancelled edit"
                 return m, nil
             default:
+                if m.cellEditor.handleKey(msg) && m.editErr != "" {
+                    m.editErr = ""
+                    m.status = m.editHint()
+                }
+                return m, nil
+            }
+        }
+        // Help overlay
+        if m.helpActive {
+            return m.updateHelp(msg)
+        }
+        // Command palette
+        if m.paletteActive {
+            return m.updatePalette(msg)
+        }
+        // Confirmation before quitting with staged changes
+        if m.confirmQuitActive {
+            switch msg.String() {
+            case "y", "Y":
+                return m.quit()
+            default:
+                m.confirmQuitActive = false
+                m.status = "cancelled"
                 return m, nil
             }
         }
@@ -75,13 +101,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
         if m.confirmDeleteActive {
             switch msg.String() {
             case "y", "Y":
+                if m.confirmDeleteType == "row" {
+                    prev := m.selRow
+                    if err := m.deleteCurrentRow(); err != nil {
+                        m.status = fmt.Sprintf("delete error: %v", err)
+                    } else {
+                        m.status = "deleted row " + m.confirmDeleteTarget
+                        if m.trashEnabled && !isTrashTable(m.previewTable) {
+                            m.status += " (copied to " + trashTableName(m.previewTable) + ")"
+                        }
+                        if prev > 0 && prev == len(m.preview)-1 { m.selRow = prev - 1 }
+                        m.refreshPreview()
+                    }
+                    m.confirmDeleteActive = false
+                    m.confirmDeleteTarget = ""
+                    return m, nil
+                }
                 if err := m.deleteCurrentTable(); err != nil {
                     m.status = fmt.Sprintf("drop %s error: %v", m.confirmDeleteType, err)
                 } else {
                     m.status = fmt.Sprintf("dropped %s %s", m.confirmDeleteType, m.confirmDeleteTarget)
                     // reload tables
                     if m.db != nil {
-                        if t, err := listTables(m.db); err == nil {
+                        if t, err := listTables(m.conn()); err == nil {
                             sort.Strings(t)
                             m.allTables = t
                             // keep filter
@@ -101,6 +143,59 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                 return m, nil
             }
         }
+        // Status-line prompt (export path, ...)
+        if m.promptActive {
+            return m.updatePrompt(msg)
+        }
+        // Row filter prompt for the selected column
+        if m.filterActive {
+            switch msg.Type {
+            case tea.KeyRunes, tea.KeySpace:
+                m.filterBuffer += string(msg.Runes)
+            case tea.KeyBackspace:
+                r := []rune(m.filterBuffer)
+                if len(r) > 0 {
+                    m.filterBuffer = string(r[:len(r)-1])
+                }
+            case tea.KeyEnter:
+                f, err := parseFilter(m.filterCol, m.filterBuffer)
+                if err != nil {
+                    m.status = fmt.Sprintf("filter error: %v", err)
+                    return m, nil
+                }
+                m.filterActive = false
+                m.filterBuffer = ""
+                m.addFilter(f)
+                if !strings.Contains(strings.ToLower(m.status), "error") {
+                    m.status = fmt.Sprintf("filter: %s (%d rows)", f.label(), m.totalRows)
+                }
+            case tea.KeyEsc:
+                m.filterActive = false
+                m.filterBuffer = ""
+                m.status = "cancelled filter"
+            }
+            return m, nil
+        }
+        // Schema tab with focus: scroll its contents
+        if m.schemaTab && m.focusPreview {
+            return m.updateSchema(msg)
+        }
+        // Hex viewer of a blob cell
+        if m.hexActive {
+            return m.updateHexView(msg)
+        }
+        // Referencing rows list
+        if m.refsActive {
+            return m.updateRefs(msg)
+        }
+        // Full-row detail view
+        if m.detailActive {
+            return m.updateDetail(msg)
+        }
+        // The query pane takes all keys while open
+        if m.queryActive {
+            return m.updateQueryPane(msg)
+        }
         // If currently searching, handle input editing first
         if m.searchActive {
             switch msg.Type {
@@ -123,83 +218,63 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                     m.applyFilter()
                 }
                 return m, nil
-            case tea.KeyEnter:
+            }
+            // Enter, Esc and navigation while searching
+            switch m.actionFor(msg.String(), "Search", "Any view") {
+            case "quit":
+                return m.quit()
+            case "keep":
                 m.searchActive = false
-                return m, nil
-            case tea.KeyEsc:
+            case "clear":
                 m.searchActive = false
                 if m.searchQuery != "" {
                     m.searchQuery = ""
                     m.applyFilter()
                 }
-                return m, nil
-            }
-            // allow navigation and quitting while in search
-            switch msg.String() {
-            case "ctrl+c", "q":
-                if m.db != nil {
-                    _ = m.db.Close()
-                }
-                return m, tea.Quit
-            case "up", "k":
-                if !m.focusPreview {
-                    if m.cursor > 0 { m.cursor--; m.refreshPreview() }
-                } else {
-                    if m.selRow > 0 { m.selRow-- }
-                }
-                return m, nil
-            case "down", "j":
-                if !m.focusPreview {
-                    if m.cursor < len(m.tables)-1 { m.cursor++; m.refreshPreview() }
-                } else {
-                    if m.selRow+1 < len(m.preview) { m.selRow++ }
-                }
-                return m, nil
-            case "left":
-                if m.focusPreview {
-                    if m.selCol > 0 { m.selCol-- } else { m.focusPreview = false }
-                }
-                return m, nil
+            case "up":
+                if m.cursor > 0 { m.cursor--; m.refreshPreview() }
+            case "down":
+                if m.cursor < len(m.tables)-1 { m.cursor++; m.refreshPreview() }
             case "right":
-                if !m.focusPreview {
-                    m.focusPreview = true
-                    m.searchActive = false
-                    if m.selRow >= len(m.preview) { m.selRow = 0 }
-                    if m.selCol >= len(m.previewColumns) { m.selCol = 0 }
-                } else if m.selCol+1 < len(m.previewColumns) {
-                    m.selCol++
-                }
-                return m, nil
+                m.focusPreview = true
+                m.searchActive = false
+                if m.selRow >= len(m.preview) { m.selRow = 0 }
+                if m.selCol >= len(m.previewColumns) { m.selCol = 0 }
             }
             return m, nil
         }
-        switch msg.String() {
-        case "ctrl+c", "q":
-            if m.db != nil {
-                _ = m.db.Close()
-            }
-            return m, tea.Quit
-        case "c":
+        // the focused pane's bindings take precedence over the global ones
+        mode := "Table list"
+        if m.focusPreview { mode = "Preview focus" }
+        switch m.actionFor(msg.String(), mode, "Global", "Any view") {
+        case "quit":
+            return m.quit()
+        case "edit":
             // begin editing the current cell when focus is on preview
-            if m.focusPreview && m.selRow >= 0 && m.selRow < len(m.preview) && m.selCol >= 0 && m.selCol < len(m.previewColumns) {
-                m.editingActive = true
-                // seed buffer with current cell text
-                cur := m.preview[m.selRow]
-                if m.selCol < len(cur) { m.editBuffer = cur[m.selCol] } else { m.editBuffer = "" }
-                m.status = fmt.Sprintf("editing %s", m.previewColumns[m.selCol])
-            }
-        case "left", "h":
             if m.focusPreview {
-                if msg.String() == "h" {
-                    // treat like left as well
-                }
+                m.beginCellEdit()
+            }
+        case "edit-external":
+            // edit the current cell in $EDITOR
+            if m.focusPreview {
+                return m, m.openExternalEditor()
+            }
+        case "detail":
+            // open the full-row detail view
+            if m.focusPreview && m.selRow >= 0 && m.selRow < len(m.preview) {
+                m.detailActive = true
+                m.detailScroll = 0
+            }
+        case "left":
+            if m.focusPreview {
                 if m.selCol > 0 {
                     m.selCol--
                 } else {
                     m.focusPreview = false
                 }
+                m.scrollToSelCol()
             }
-        case "right", "l":
+        case "right":
             if !m.focusPreview {
                 m.focusPreview = true
                 if m.selRow >= len(m.preview) { m.selRow = 0 }
@@ -207,26 +282,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
             } else if m.selCol+1 < len(m.previewColumns) {
                 m.selCol++
             }
-        case "/":
+            m.scrollToSelCol()
+        case "schema":
+            // switch the right pane between data and schema
+            m.schemaTab = !m.schemaTab
+            if m.schemaTab {
+                m.loadSchema()
+            }
+            return m, nil
+        case "palette":
+            m.openPalette()
+            return m, nil
+        case "help":
+            m.helpActive = true
+            m.helpScroll = 0
+            return m, nil
+        case "query":
+            // open the ad-hoc SQL query pane
+            m.queryActive = true
+            m.queryFocusResult = false
+            return m, nil
+        case "search":
             if !m.focusPreview {
                 m.searchActive = true
             }
             return m, nil
-        case "x":
+        case "delete":
             if m.focusPreview {
-                prev := m.selRow
-                if err := m.deleteCurrentRow(); err != nil {
-                    m.status = fmt.Sprintf("delete error: %v", err)
-                } else {
-                    m.status = "deleted row"
-                    if prev > 0 && prev == len(m.preview)-1 { m.selRow = prev - 1 }
-                    m.refreshPreview()
+                if m.denyWrite("deleting rows") { return m, nil }
+                // request confirmation to delete the selected row
+                if m.selRow >= 0 && m.selRow < len(m.preview) {
+                    m.confirmDeleteActive = true
+                    m.confirmDeleteType = "row"
+                    m.confirmDeleteTarget = m.rowLabel(m.selRow)
+                    m.status = fmt.Sprintf("delete row %s from %s? (y/n)", m.confirmDeleteTarget, m.previewTable)
                 }
             } else if m.cursor >= 0 && m.cursor < len(m.tables) {
                 // from the left pane: request confirmation to drop table or view
+                if m.denyWrite("dropping tables") { return m, nil }
                 name := m.tables[m.cursor]
                 // determine if table or view
-                t, err := getObjectType(m.db, name)
+                t, err := getObjectType(m.conn(), name)
                 if err != nil { m.status = fmt.Sprintf("lookup type error: %v", err); return m, nil }
                 m.confirmDeleteActive = true
                 m.confirmDeleteTarget = name
@@ -234,35 +330,213 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                 m.status = fmt.Sprintf("drop %s %s? (y/n)", t, name)
             }
             return m, nil
-        case "up", "k":
+        case "up":
             if !m.focusPreview {
                 if m.cursor > 0 { m.cursor--; m.refreshPreview() }
             } else {
-                if m.selRow > 0 { m.selRow-- }
+                if m.selRow > 0 {
+                    m.selRow--
+                } else if m.pageOffset > 0 {
+                    // scroll into the previous page, landing on its last row
+                    m.gotoPage(m.prevPage)
+                    m.selRow = max(0, len(m.preview)-1)
+                }
             }
-        case "down", "j":
+        case "down":
             if !m.focusPreview {
                 if m.cursor < len(m.tables)-1 { m.cursor++; m.refreshPreview() }
             } else {
-                if m.selRow+1 < len(m.preview) { m.selRow++ }
-            }
-        case "y":
-            if m.focusPreview && m.selRow >= 0 && m.selRow < len(m.preview) && m.selCol >= 0 && m.selCol < len(m.previewColumns) {
-                val := ""
-                if len(m.preview) > 0 {
-                    row := m.preview[m.selRow]
-                    if m.selCol < len(row) {
-                        val = row[m.selCol]
-                    }
+                if m.selRow+1 < len(m.preview) {
+                    m.selRow++
+                } else if m.pageOffset+len(m.preview) < m.totalRows {
+                    m.gotoPage(m.nextPage)
+                    m.selRow = 0
+                }
+            }
+        case "pin":
+            // pin/unpin PK columns on the left while scrolling
+            if m.focusPreview {
+                m.freezePK = !m.freezePK
+                m.colOffset = 0
+                m.scrollToSelCol()
+                if m.freezePK { m.status = "PK columns pinned" } else { m.status = "PK columns unpinned" }
+            }
+        case "sort":
+            // cycle sort on the selected column: asc -> desc -> off
+            if m.focusPreview && m.selCol >= 0 && m.selCol < len(m.previewColumns) {
+                col := m.previewColumns[m.selCol]
+                if m.isJSONColumn(col) {
+                    m.status = "can't sort on a computed JSON column"
+                    break
+                }
+                m.cycleSort(col)
+                switch {
+                case m.sortCol == "":
+                    m.status = "unsorted"
+                case m.sortDesc:
+                    m.status = fmt.Sprintf("sorted by %s descending", col)
+                default:
+                    m.status = fmt.Sprintf("sorted by %s ascending", col)
+                }
+            }
+        case "filter":
+            // prompt for a row condition on the selected column
+            if m.focusPreview && m.selCol >= 0 && m.selCol < len(m.previewColumns) {
+                if m.isJSONColumn(m.previewColumns[m.selCol]) {
+                    m.status = "can't filter on a computed JSON column"
+                    break
+                }
+                m.filterActive = true
+                m.filterCol = m.previewColumns[m.selCol]
+                m.filterBuffer = ""
+                m.status = fmt.Sprintf("filter %s: = x, LIKE %%foo%%, > 10, IS NULL ... (Enter apply, Esc cancel)", m.filterCol)
+            }
+        case "unfilter":
+            // remove the filter on the selected column (or the latest one)
+            if m.focusPreview {
+                col := ""
+                if m.selCol >= 0 && m.selCol < len(m.previewColumns) { col = m.previewColumns[m.selCol] }
+                if f, ok := m.removeFilter(col); ok {
+                    m.status = fmt.Sprintf("removed filter %s", f.label())
+                } else {
+                    m.status = "no filters"
+                }
+            }
+        case "follow":
+            // follow the selected foreign key to its parent row
+            if m.focusPreview {
+                if err := m.followForeignKey(); err != nil {
+                    m.status = fmt.Sprintf("follow error: %v", err)
+                } else {
+                    m.status = fmt.Sprintf("parent row in %s (b to go back)", m.previewTable)
+                }
+            }
+        case "refs":
+            // list child tables referencing the selected row
+            if m.focusPreview {
+                if err := m.loadReferences(); err != nil {
+                    m.status = fmt.Sprintf("references error: %v", err)
+                }
+            }
+        case "back":
+            if m.popNav() {
+                m.status = fmt.Sprintf("back to %s", m.previewTable)
+            }
+        case "staging":
+            // toggle staging mode (edits held in one transaction)
+            if m.denyWrite("staging") { break }
+            if m.tx == nil {
+                if err := m.beginStaging(); err != nil {
+                    m.status = fmt.Sprintf("staging error: %v", err)
+                } else {
+                    m.status = "staging on: C commit, X roll back"
+                }
+            } else if m.pending > 0 {
+                m.status = fmt.Sprintf("%d pending change(s): commit (C) or roll back (X) first", m.pending)
+            } else {
+                if err := m.rollbackStaging(true); err != nil {
+                    m.status = fmt.Sprintf("staging error: %v", err)
+                } else {
+                    m.status = "staging off"
                 }
-                if err := copyToClipboard(val); err != nil {
-                    m.status = fmt.Sprintf("copy error: %v", err)
+            }
+        case "commit":
+            if m.tx != nil {
+                n := m.pending
+                if err := m.commitStaging(false); err != nil {
+                    m.status = fmt.Sprintf("commit error: %v", err)
                 } else {
-                    m.status = "copied"
+                    m.status = fmt.Sprintf("committed %d change(s)", n)
                 }
+                m.refreshPreview()
             }
-        case "i":
+        case "rollback":
+            if m.tx != nil {
+                n := m.pending
+                if err := m.rollbackStaging(false); err != nil {
+                    m.status = fmt.Sprintf("rollback error: %v", err)
+                } else {
+                    m.status = fmt.Sprintf("rolled back %d change(s)", n)
+                }
+                if t, err := listTables(m.conn()); err == nil {
+                    sort.Strings(t)
+                    m.allTables = t
+                }
+                m.applyFilter()
+            }
+        case "restore":
+            // restore the selected row of a trash table into its source table
+            if m.focusPreview && isTrashTable(m.previewTable) && !m.denyWrite("restoring rows") {
+                prev := m.selRow
+                if src, err := m.restoreFromTrash(); err != nil {
+                    m.status = fmt.Sprintf("restore error: %v", err)
+                } else {
+                    m.status = "restored row into " + src
+                    if prev > 0 && prev == len(m.preview)-1 { m.selRow = prev - 1 }
+                    m.refreshPreview()
+                }
+            }
+        case "undo":
+            if m.denyWrite("undo") { break }
+            if what, err := m.undo(); err != nil {
+                m.status = fmt.Sprintf("undo error: %v", err)
+            } else {
+                m.status = "undid " + what
+                m.refreshPreview()
+            }
+        case "redo":
+            if m.denyWrite("redo") { break }
+            if what, err := m.redo(); err != nil {
+                m.status = fmt.Sprintf("redo error: %v", err)
+            } else {
+                m.status = "redid " + what
+                m.refreshPreview()
+            }
+        case "next-page":
+            m.gotoPage(m.nextPage)
+        case "prev-page":
+            m.gotoPage(m.prevPage)
+        case "first-page":
+            if m.focusPreview {
+                m.gotoPage(m.firstPage)
+                m.selRow = 0
+            }
+        case "last-page":
+            if m.focusPreview {
+                m.gotoPage(m.lastPage)
+                m.selRow = max(0, len(m.preview)-1)
+            }
+        case "copy":
+            if m.focusPreview {
+                m.copySelectedCell()
+            }
+        case "hex":
+            // show the selected cell as a hex dump
             if m.focusPreview {
+                m.openHexView()
+            }
+        case "json":
+            // add a json_extract column for the selected column, or remove a computed one
+            if m.focusPreview && m.selCol >= 0 && m.selCol < len(m.previewColumns) {
+                col := m.previewColumns[m.selCol]
+                if m.removeJSONColumn(col) {
+                    m.status = "removed column " + col
+                } else {
+                    m.openPrompt("json-column", "json_extract path on "+col, "$.")
+                }
+            }
+        case "import":
+            // import a CSV/NDJSON file into a new or the selected table
+            if m.db != nil && !m.denyWrite("importing") {
+                m.openPrompt("import", "import file (.csv .tsv .ndjson .jsonl .json)", "")
+            }
+        case "export":
+            // export the whole table with the active filters and sort
+            if m.previewTable != "" {
+                m.openPrompt("export", "export "+m.previewTable+" to (.csv .tsv .json .ndjson .md .sql)", m.previewTable+".csv")
+            }
+        case "insert":
+            if m.focusPreview && !m.denyWrite("inserting rows") {
                 if len(m.preview) == 0 {
                     if err := m.insertEmptyRow(); err != nil {
                         m.status = fmt.Sprintf("insert error: %v", err)
@@ -279,10 +553,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                     }
                 }
             }
-        case "r":
+        case "reload":
             // reload tables
             if m.db != nil {
-                t, err := listTables(m.db)
+                t, err := listTables(m.conn())
                 if err != nil {
                     m.status = fmt.Sprintf("reload error: %v", err)
                 } else {
@@ -295,19 +569,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
     case tea.WindowSizeMsg:
         m.width = msg.Width
         m.height = msg.Height
+        m.scrollToSelCol()
     }
     return m, nil
 }
 
-func (m model) View() string {
-    if m.db == nil {
-        return fmt.Sprintf("DB not open. %s\n", m.status)
-    }
+// defaultLeftWidth is the width of the tables list unless --left-width sets it.
+const defaultLeftWidth = 30
 
-    // Layout: left column for tables, right column for preview.
-    leftWidth := 30
-    if m.width > 0 && m.width < 80 {
-        leftWidth = m.width / 3
+// paneWidths returns the widths of the tables list and the preview pane.
+func (m model) paneWidths() (int, int) {
+    leftWidth := defaultLeftWidth
+    if m.leftWidth > 0 { leftWidth = m.leftWidth }
+    // on narrow terminals give the preview at least two thirds
+    if m.width > 0 && m.width < leftWidth+50 {
+        leftWidth = min(leftWidth, m.width/3)
     }
     rightWidth := 80
     if m.width > 0 {
@@ -316,10 +592,50 @@ func (m model) View() string {
             rightWidth = 20
         }
     }
+    return leftWidth, rightWidth
+}
+
+// gridWidth is the room available for preview columns (pane minus row gutter).
+func (m model) gridWidth() int {
+    _, rightWidth := m.paneWidths()
+    return max(1, rightWidth-2)
+}
+
+// gridRows is how many grid rows fit in the right pane below used lines,
+// leaving room for the status line; 0 (no limit) before the terminal size is
+// known.
+func (m model) gridRows(used int) int {
+    if m.height <= 0 {
+        return 0
+    }
+    return max(1, m.height-used-2)
+}
+
+// scrollToSelCol adjusts colOffset so the selected column is on screen.
+func (m *model) scrollToSelCol() {
+    g := m.previewGrid()
+    m.colOffset = scrollOffsetFor(g.colWidths(), g.frozen, m.selCol, m.colOffset, g.width)
+}
+
+func (m model) View() string {
+    if m.db == nil {
+        return fmt.Sprintf("DB not open. %s\n", m.status)
+    }
+    if m.helpActive {
+        return m.renderHelp()
+    }
+
+    // Layout: left column for tables, right column for preview.
+    leftWidth, rightWidth := m.paneWidths()
+    m.scrollToSelCol()
 
     // Render tables list
     var left strings.Builder
-    left.WriteString(styleHeader.Render("Tables (j/k or ↓/↑, → to preview, / search, r reload, q quit)") + "\n")
+    header := styleHeader.Render("Tables (? help, → preview, / search, : query, " + keyLabel(m.keys.key("quit")) + " quit)")
+    if badge := m.readOnlyBadge(); badge != "" {
+        header = badge + " " + header
+    }
+    left.WriteString(header + "\n")
     if m.searchActive || m.searchQuery != "" {
         left.WriteString(styleSearch.Render("/" + m.searchQuery) + "\n")
     }
@@ -335,64 +651,42 @@ func (m model) View() string {
 
     // Render preview table
     var right strings.Builder
-    if len(m.tables) == 0 {
+    if m.paletteActive {
+        m.renderPalette(&right, rightWidth)
+    } else if m.queryActive {
+        m.renderQueryPane(&right, rightWidth)
+    } else if m.hexActive && len(m.tables) > 0 {
+        m.renderHexView(&right, rightWidth)
+    } else if m.editOverlay() && len(m.tables) > 0 {
+        m.renderCellEditor(&right, rightWidth)
+    } else if m.schemaTab && len(m.tables) > 0 {
+        m.renderSchema(&right, rightWidth)
+    } else if m.refsActive && len(m.tables) > 0 {
+        m.renderRefs(&right, rightWidth)
+    } else if m.detailActive && len(m.tables) > 0 {
+        m.renderDetail(&right, rightWidth)
+    } else if len(m.tables) == 0 {
         right.WriteString("No tables found.\n")
     } else {
-        title := fmt.Sprintf("Preview: %s (up to 10 rows)", m.tables[m.cursor])
+        title := fmt.Sprintf("Preview: %s (%s)", m.tables[m.cursor], m.pagePosition())
+        g := m.previewGrid()
+        if span := columnSpan(g); span != "" { title += " " + span }
         if m.focusPreview { title += " " + styleFocusTag.Render("FOCUS") }
         if m.editingActive { title += " " + stylePrompt.Render("EDITING") }
         right.WriteString(styleHeader.Render(title) + "\n")
+        used := 3 // title and grid header
+        if m.filterActive {
+            used++
+            right.WriteString(styleSearch.Render(fmt.Sprintf("where %s %s", m.filterCol, m.filterBuffer)) + styleEditCursor.Render(" ") + "\n")
+        }
+        if len(m.filters) > 0 {
+            used++
+            right.WriteString(m.renderFilterChips() + "\n")
+        }
         if len(m.previewColumns) > 0 {
-            // compute column widths based on available rightWidth minus the 2-char row gutter
-            cwAvail := rightWidth - 2
-            if cwAvail < 1 { cwAvail = 1 }
-            colWidths := computeColumnWidths(m.previewColumns, m.preview, cwAvail)
-            // header (align with row gutter)
-            right.WriteString("  ")
-            for i, c := range m.previewColumns {
-                headerText := c
-                // add selection marker before truncation
-                isSelectedHeader := m.focusPreview && i == m.selCol
-                if isSelectedHeader {
-                    headerText = "*" + headerText
-                }
-                headerText = truncateCell(headerText, colWidths[i])
-                if isSelectedHeader {
-                    headerText = styleColSelect.Render(headerText)
-                }
-                cell := padRightANSI(headerText, colWidths[i])
-                right.WriteString(cell)
-                if i < len(m.previewColumns)-1 {
-                    right.WriteString(" ")
-                }
-            }
-            right.WriteString("\n")
-            // separator (align with row gutter)
-            right.WriteString("  ")
-            for i := range m.previewColumns {
-                right.WriteString(strings.Repeat("-", colWidths[i]))
-                if i < len(m.previewColumns)-1 {
-                    right.WriteString(" ")
-                }
-            }
-            right.WriteString("\n")
-            // rows
-            for ri, row := range m.preview {
-                // row cursor in preview focus
-                if m.focusPreview && ri == m.selRow { right.WriteString(styleCursor.Render("> ")) } else { right.WriteString("  ") }
-                for i, cell := range row {
-                    // If editing this cell, render buffer
-                    if m.editingActive && m.focusPreview && ri == m.selRow && i == m.selCol {
-                        cell = m.editBuffer
-                    }
-                    cell = truncateCell(cell, colWidths[i])
-                    right.WriteString(padRightANSI(cell, colWidths[i]))
-                    if i < len(row)-1 {
-                        right.WriteString(" ")
-                    }
-                }
-                right.WriteString("\n")
-            }
+            // pages can be longer than the screen; show the rows around the cursor
+            g.maxRows = m.gridRows(used)
+            renderGrid(&right, g)
         } else {
             right.WriteString("(no columns)\n")
         }
@@ -427,6 +721,14 @@ func (m model) View() string {
         out.WriteString(r)
         out.WriteString("\n")
     }
+    badge := m.stagingBadge()
+    if m.promptActive {
+        out.WriteString("\n" + m.renderPrompt() + "\n")
+        return out.String()
+    }
+    if badge != "" && m.status == "" {
+        out.WriteString("\n" + badge + "\n")
+    }
     if m.status != "" {
         // Highlight confirmation prompts vs info/errors
         rendered := m.status
@@ -438,11 +740,107 @@ func (m model) View() string {
         } else {
             rendered = styleInfo.Render(m.status)
         }
+        if badge != "" {
+            rendered = badge + " " + rendered
+        }
         out.WriteString("\n" + rendered + "\n")
     }
     return out.String()
 }
 
+// selectedCell returns the text of the selected preview cell.
+func (m model) selectedCell() (string, bool) {
+    if m.selRow < 0 || m.selRow >= len(m.preview) || m.selCol < 0 || m.selCol >= len(m.previewColumns) {
+        return "", false
+    }
+    row := m.preview[m.selRow]
+    if m.selCol >= len(row) {
+        return "", true
+    }
+    return row[m.selCol], true
+}
+
+// beginCellEdit starts inline editing of the selected cell, seeded with its text.
+func (m *model) beginCellEdit() {
+    cur, ok := m.selectedCell()
+    if !ok || m.denyWrite("editing") {
+        return
+    }
+    col := m.previewColumns[m.selCol]
+    if m.isJSONColumn(col) {
+        m.status = fmt.Sprintf("%s is a computed JSON column and can't be edited", col)
+        return
+    }
+    if info, ok := m.fieldInfo(col); ok && columnKind(info.Type) == "boolean" {
+        // booleans toggle instead of opening the editor
+        if err := m.toggleBoolCell(cur); err != nil {
+            m.status = fmt.Sprintf("update error: %v", err)
+        } else {
+            m.status = fmt.Sprintf("set %s to %s", col, m.cellEditor.value())
+        }
+        m.cellEditor.setValue("")
+        m.refreshPreview()
+        return
+    }
+    m.editingActive = true
+    m.editErr = ""
+    m.cellEditor.setValue(m.editSeed(cur))
+    m.status = m.editHint()
+}
+
+// editHint is the status line shown while editing the selected cell.
+func (m model) editHint() string {
+    col := m.previewColumns[m.selCol]
+    kind := ""
+    if info, ok := m.fieldInfo(col); ok && info.Type != "" {
+        kind = " " + strings.ToUpper(info.Type)
+    }
+    return fmt.Sprintf("editing %s%s (Enter save, Alt+Enter newline, Esc cancel)", col, kind)
+}
+
+// copySelectedCell copies the selected cell to the clipboard.
+func (m *model) copySelectedCell() {
+    val, ok := m.selectedCell()
+    if !ok {
+        return
+    }
+    if err := copyToClipboard(val); err != nil {
+        m.status = fmt.Sprintf("copy error: %v", err)
+    } else {
+        m.status = "copied"
+    }
+}
+
+// pagePosition describes where the preview sits in the table, e.g. "row 12 of 340".
+func (m model) pagePosition() string {
+    if len(m.preview) == 0 {
+        return fmt.Sprintf("row 0 of %d", m.totalRows)
+    }
+    row := m.pageOffset + 1
+    if m.focusPreview {
+        row += m.selRow
+    }
+    return fmt.Sprintf("row %d of %d", row, m.totalRows)
+}
+
+// previewGrid describes the preview table for renderGrid.
+func (m model) previewGrid() gridSpec {
+    return gridSpec{
+        cols:     m.previewColumns,
+        rows:     m.preview,
+        frozen:   m.frozenColumns(),
+        offset:   m.colOffset,
+        width:    m.gridWidth(),
+        focused:  m.focusPreview,
+        selRow:   m.selRow,
+        selCol:   m.selCol,
+        editor:   m.activeCellEditor(),
+        sortCol:  m.sortCol,
+        sortDesc: m.sortDesc,
+        changed:  m.stagedGridCells(),
+    }
+}
+
 // equalStrings returns true if the two string slices are identical in length and element order.
 func equalStrings(a, b []string) bool {
     if len(a) != len(b) {
@@ -0,0 +1,315 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+)
+
+// maxUndo caps how many mutations the undo stack remembers.
+const maxUndo = 200
+
+type undoKind int
+
+const (
+    undoUpdate undoKind = iota // one cell changed from before to after
+    undoDelete                 // a row was deleted; row holds its values
+    undoInsert                 // a row was inserted; row holds its values
+)
+
+// rowLocator identifies a row by its key columns (PK or rowid) and raw values.
+type rowLocator struct {
+    cols []string
+    vals []any
+}
+
+// where returns "k1 = ? AND k2 = ?" with its params.
+func (l rowLocator) where() (string, []any) {
+    parts := make([]string, len(l.cols))
+    for i, c := range l.cols {
+        parts[i] = keyExpr(c) + " = ?"
+    }
+    return strings.Join(parts, " AND "), l.vals
+}
+
+// with returns a copy of l where column col (if it is a key column) has value v.
+func (l rowLocator) with(col string, v any) rowLocator {
+    out := rowLocator{cols: l.cols, vals: append([]any(nil), l.vals...)}
+    if i := findColIndex(l.cols, col); i >= 0 {
+        out.vals[i] = v
+    }
+    return out
+}
+
+// rowSnapshot holds every stored column of a row, as raw values.
+type rowSnapshot struct {
+    cols []string
+    vals []any
+}
+
+// undoEntry is one reversible mutation.
+type undoEntry struct {
+    kind   undoKind
+    table  string
+    loc    rowLocator
+    col    string // undoUpdate
+    before any    // undoUpdate
+    after  any    // undoUpdate
+    row    rowSnapshot // undoDelete / undoInsert
+    trash  int64       // undoDelete: rowid of the row's copy in the trash table, 0 if none
+}
+
+func (e undoEntry) describe() string {
+    switch e.kind {
+    case undoUpdate:
+        return fmt.Sprintf("edit of %s.%s", e.table, e.col)
+    case undoDelete:
+        return fmt.Sprintf("delete from %s", e.table)
+    default:
+        return fmt.Sprintf("insert into %s", e.table)
+    }
+}
+
+// keyExpr quotes a key column, leaving the rowid pseudo-column bare.
+func keyExpr(c string) string {
+    if c == "rowid" {
+        return "rowid"
+    }
+    return quoteIdent(c)
+}
+
+// rawExpr selects a column without the driver's declared-type conversion
+// (e.g. DATETIME text becoming time.Time), so values round-trip unchanged.
+func rawExpr(c string) string {
+    return "+" + keyExpr(c)
+}
+
+// rowLocator returns the key of preview row ri.
+func (m model) rowLocator(ri int) (rowLocator, bool) {
+    if ri < 0 || ri >= len(m.previewKeys) || len(m.keyCols) == 0 {
+        return rowLocator{}, false
+    }
+    vals := m.previewKeys[ri]
+    vals = vals[len(vals)-len(m.keyCols):] // skip the sort value, if any
+    return rowLocator{cols: m.keyCols, vals: append([]any(nil), vals...)}, true
+}
+
+// readCell returns the raw stored value of col in the located row.
+func readCell(db dbConn, table string, loc rowLocator, col string) (any, error) {
+    where, params := loc.where()
+    var v any
+    q := fmt.Sprintf("SELECT %s FROM %s WHERE %s", rawExpr(col), quoteIdent(table), where)
+    err := db.QueryRow(q, params...).Scan(&v)
+    return v, err
+}
+
+// snapshotRow reads every stored column of the located row (plus rowid when
+// that is the key) so it can be deleted or re-inserted exactly.
+func snapshotRow(db dbConn, table string, loc rowLocator) (rowSnapshot, error) {
+    info, err := getTableInfo(db, table)
+    if err != nil {
+        return rowSnapshot{}, err
+    }
+    var cols []string
+    if len(loc.cols) == 1 && loc.cols[0] == "rowid" {
+        cols = append(cols, "rowid")
+    }
+    for _, c := range info {
+        cols = append(cols, c.Name)
+    }
+    exprs := make([]string, len(cols))
+    for i, c := range cols {
+        exprs[i] = rawExpr(c)
+    }
+    where, params := loc.where()
+    q := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(exprs, ", "), quoteIdent(table), where)
+    vals := make([]any, len(cols))
+    dest := make([]any, len(cols))
+    for i := range vals {
+        dest[i] = &vals[i]
+    }
+    if err := db.QueryRow(q, params...).Scan(dest...); err != nil {
+        return rowSnapshot{}, err
+    }
+    return rowSnapshot{cols: cols, vals: vals}, nil
+}
+
+// pushUndo records a mutation and clears the redo stack.
+func (m *model) pushUndo(e undoEntry) {
+    m.undoStack = append(m.undoStack, e)
+    if len(m.undoStack) > maxUndo {
+        m.undoStack = m.undoStack[len(m.undoStack)-maxUndo:]
+    }
+    m.redoStack = nil
+}
+
+// recordUpdate remembers a cell edit; before must be read ahead of the UPDATE.
+func (m *model) recordUpdate(table string, loc rowLocator, col string, before, after any) {
+    m.pushUndo(undoEntry{kind: undoUpdate, table: table, loc: loc, col: col, before: before, after: after})
+}
+
+// recordInsert remembers the row created by the latest INSERT, located by rowid.
+// Tables without rowid cannot be located this way and are not recorded.
+func (m *model) recordInsert(table string) {
+    loc := rowLocator{cols: []string{"rowid"}, vals: []any{m.lastInsertID}}
+    row, err := snapshotRow(m.conn(), table, loc)
+    if err != nil {
+        return
+    }
+    if len(m.keyCols) > 0 && m.keyCols[0] != "rowid" {
+        // prefer the PK so replays don't depend on rowid
+        pk := rowLocator{cols: m.keyCols}
+        for _, c := range m.keyCols {
+            v, _ := row.value(c)
+            pk.vals = append(pk.vals, v)
+        }
+        loc = pk
+    }
+    m.pushUndo(undoEntry{kind: undoInsert, table: table, loc: loc, row: row})
+}
+
+func (r rowSnapshot) value(col string) (any, bool) {
+    if i := findColIndex(r.cols, col); i >= 0 {
+        return r.vals[i], true
+    }
+    return nil, false
+}
+
+// removeRow deletes the located row, but only if it still holds exactly the
+// snapshot values.
+func (m *model) removeRow(table string, loc rowLocator, row rowSnapshot) error {
+    where, params := loc.where()
+    for i, c := range row.cols {
+        where += fmt.Sprintf(" AND %s IS ?", rawExpr(c))
+        params = append(params, row.vals[i])
+    }
+    res, err := m.conn().Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdent(table), where), params...)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n != 1 {
+        return fmt.Errorf("row in %s changed since, not touching it", table)
+    }
+    m.noteChange()
+    return nil
+}
+
+// restoreRow re-inserts a snapshot, refusing if its key is taken again.
+func (m *model) restoreRow(table string, loc rowLocator, row rowSnapshot) error {
+    where, params := loc.where()
+    var n int
+    if err := m.conn().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteIdent(table), where), params...).Scan(&n); err != nil {
+        return err
+    }
+    if n > 0 {
+        return fmt.Errorf("a row with the same key exists in %s, not overwriting it", table)
+    }
+    names := make([]string, len(row.cols))
+    for i, c := range row.cols {
+        names[i] = keyExpr(c)
+    }
+    q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdent(table), strings.Join(names, ", "), placeholders(len(row.cols)))
+    _, err := m.conn().Exec(q, row.vals...)
+    if err == nil {
+        m.noteChange()
+    }
+    return err
+}
+
+// setCellIf changes col from "from" to "to", only if it still holds "from".
+func (m *model) setCellIf(table string, loc rowLocator, col string, from, to any) error {
+    where, params := loc.where()
+    q := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s AND %s IS ?", quoteIdent(table), quoteIdent(col), where, rawExpr(col))
+    args := append([]any{to}, params...)
+    args = append(args, from)
+    res, err := m.conn().Exec(q, args...)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n != 1 {
+        return fmt.Errorf("%s.%s changed since, not overwriting it", table, col)
+    }
+    m.noteChange()
+    return nil
+}
+
+// replay applies e backwards (undo) or forwards (redo), after checking the
+// row still looks the way the entry expects.
+func (m *model) replay(e *undoEntry, undo bool) error {
+    switch e.kind {
+    case undoUpdate:
+        if undo {
+            return m.setCellIf(e.table, e.loc.with(e.col, e.after), e.col, e.after, e.before)
+        }
+        return m.setCellIf(e.table, e.loc, e.col, e.before, e.after)
+    case undoDelete:
+        if undo {
+            if err := m.restoreRow(e.table, e.loc, e.row); err != nil {
+                return err
+            }
+            // the row is back, so its trash copy must not be restored again
+            if e.trash != 0 {
+                if err := m.dropFromTrash(e.table, e.trash); err != nil {
+                    return fmt.Errorf("row restored, but its trash copy is left: %w", err)
+                }
+                e.trash = 0
+            }
+            return nil
+        }
+        if m.trashEnabled && !isTrashTable(e.table) {
+            id, err := m.copyToTrash(e.table, e.loc)
+            if err != nil {
+                return fmt.Errorf("copy to trash: %w", err)
+            }
+            if err := m.removeRow(e.table, e.loc, e.row); err != nil {
+                _ = m.dropFromTrash(e.table, id)
+                return err
+            }
+            e.trash = id
+            return nil
+        }
+        return m.removeRow(e.table, e.loc, e.row)
+    case undoInsert:
+        if undo {
+            return m.removeRow(e.table, e.loc, e.row)
+        }
+        return m.restoreRow(e.table, e.loc, e.row)
+    }
+    return nil
+}
+
+// undo reverses the latest mutation and moves it to the redo stack.
+func (m *model) undo() (string, error) {
+    if len(m.undoStack) == 0 {
+        return "", fmt.Errorf("nothing to undo")
+    }
+    e := m.undoStack[len(m.undoStack)-1]
+    if err := m.replay(&e, true); err != nil {
+        return "", err
+    }
+    m.undoStack = m.undoStack[:len(m.undoStack)-1]
+    m.redoStack = append(m.redoStack, e)
+    return e.describe(), nil
+}
+
+// redo re-applies the latest undone mutation.
+func (m *model) redo() (string, error) {
+    if len(m.redoStack) == 0 {
+        return "", fmt.Errorf("nothing to redo")
+    }
+    e := m.redoStack[len(m.redoStack)-1]
+    if err := m.replay(&e, false); err != nil {
+        return "", err
+    }
+    m.redoStack = m.redoStack[:len(m.redoStack)-1]
+    m.undoStack = append(m.undoStack, e)
+    return e.describe(), nil
+}
+
+// dropUndoSince forgets entries recorded after mark, e.g. when the staging
+// transaction that held them is rolled back.
+func (m *model) dropUndoSince(mark int) {
+    if mark < len(m.undoStack) {
+        m.undoStack = m.undoStack[:mark]
+    }
+    m.redoStack = nil
+}
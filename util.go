@@ -4,6 +4,7 @@ import (
     "fmt"
     "strconv"
     "strings"
+    "time"
 )
 
 func truncateCell(s string, max int) string {
@@ -29,6 +30,9 @@ func formatValue(v any) string {
         return "NULL"
     }
     switch t := v.(type) {
+    case time.Time:
+        // DATE/DATETIME columns come back parsed; show them the way SQLite stores them
+        return formatTime(t)
     case []byte:
         // Try interpret as UTF-8 text; otherwise show hex length
         s := string(t)
@@ -41,6 +45,18 @@ func formatValue(v any) string {
     }
 }
 
+// formatTime renders t as SQLite date/time text: just the date at midnight
+// UTC, otherwise date and time with fractional seconds and offset when present.
+func formatTime(t time.Time) string {
+    if t.Location() == time.UTC {
+        if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+            return t.Format("2006-01-02")
+        }
+        return t.Format("2006-01-02 15:04:05.999999999")
+    }
+    return t.Format("2006-01-02 15:04:05.999999999-07:00")
+}
+
 func asInt64(v any) int64 {
     switch t := v.(type) {
     case int64:
@@ -116,4 +132,73 @@ func buildSelectExprs(insertCols []string, overrides map[string]any) ([]string,
     return exprs, params
 }
 
+// fuzzyMatch reports whether the runes of pattern appear in s in order
+// (case-insensitive). The score favours consecutive runs and early matches, so
+// higher is better. An empty pattern matches everything with score 0.
+func fuzzyMatch(pattern, s string) (int, bool) {
+    p := []rune(strings.ToLower(pattern))
+    if len(p) == 0 {
+        return 0, true
+    }
+    score, pi, run := 0, 0, 0
+    for i, r := range []rune(strings.ToLower(s)) {
+        if pi < len(p) && r == p[pi] {
+            pi++
+            run++
+            score += 1 + run*2
+            if i < 8 { score += 8 - i }
+        } else {
+            run = 0
+        }
+    }
+    if pi < len(p) {
+        return 0, false
+    }
+    return score, true
+}
+
+// wrapText splits s into lines of at most width runes, breaking at newlines
+// and preferring the last space before the limit.
+func wrapText(s string, width int) []string {
+    if width <= 0 {
+        return nil
+    }
+    var out []string
+    for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+        r := []rune(strings.ReplaceAll(para, "\t", "    "))
+        if len(r) == 0 {
+            out = append(out, "")
+            continue
+        }
+        for len(r) > width {
+            cut := width
+            for i := width; i > width/2; i-- {
+                if r[i] == ' ' {
+                    cut = i
+                    break
+                }
+            }
+            out = append(out, string(r[:cut]))
+            r = r[cut:]
+            if len(r) > 0 && r[0] == ' ' {
+                r = r[1:]
+            }
+        }
+        out = append(out, string(r))
+    }
+    return out
+}
+
+// placeholders returns n comma-separated "?" bind markers.
+func placeholders(n int) string {
+    if n <= 0 { return "" }
+    return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func reverseRows[T any](s []T) {
+    for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 { s[i], s[j] = s[j], s[i] }
+}
+
 func max(a, b int) int { if a > b { return a }; return b }
+
+func min(a, b int) int { if a < b { return a }; return b }
@@ -0,0 +1,174 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// validationError is an edit rejected before it reaches the database; the
+// editor stays open and shows it inline.
+type validationError struct {
+    col string
+    msg string
+}
+
+func (e validationError) Error() string { return fmt.Sprintf("%s: %s", e.col, e.msg) }
+
+// columnKind classifies a declared column type for editing. It follows
+// SQLite's affinity rules, with BOOL, JSON and DATE/TIME names picked out
+// first since they have NUMERIC affinity but want their own parsing.
+func columnKind(declared string) string {
+    t := strings.ToUpper(declared)
+    switch {
+    case strings.Contains(t, "BOOL"):
+        return "boolean"
+    case strings.Contains(t, "JSON"):
+        return "json"
+    case strings.Contains(t, "DATETIME") || strings.Contains(t, "TIMESTAMP"):
+        return "datetime"
+    case strings.Contains(t, "DATE"):
+        return "date"
+    case strings.Contains(t, "TIME"):
+        return "time"
+    case strings.Contains(t, "INT"):
+        return "integer"
+    case strings.Contains(t, "CHAR") || strings.Contains(t, "CLOB") || strings.Contains(t, "TEXT"):
+        return "text"
+    case t == "" || strings.Contains(t, "BLOB"):
+        return "blob"
+    case strings.Contains(t, "REAL") || strings.Contains(t, "FLOA") || strings.Contains(t, "DOUB"):
+        return "real"
+    }
+    return "numeric"
+}
+
+// Accepted input layouts for date and time columns; values are stored as typed.
+var (
+    dateLayouts     = []string{"2006-01-02"}
+    timeLayouts     = []string{"15:04", "15:04:05", "15:04:05.999999999"}
+    datetimeLayouts = []string{
+        "2006-01-02",
+        "2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02 15:04:05.999999999",
+        "2006-01-02T15:04", "2006-01-02T15:04:05", "2006-01-02T15:04:05.999999999",
+        time.RFC3339, time.RFC3339Nano, "2006-01-02 15:04:05-07:00", "2006-01-02 15:04:05.999999999-07:00",
+    }
+)
+
+func matchesLayout(s string, layouts []string) bool {
+    for _, l := range layouts {
+        if _, err := time.Parse(l, s); err == nil {
+            return true
+        }
+    }
+    return false
+}
+
+// parseBool reads the usual spellings of a boolean.
+func parseBool(s string) (bool, bool) {
+    switch strings.ToLower(strings.TrimSpace(s)) {
+    case "1", "true", "t", "yes", "y", "on":
+        return true, true
+    case "0", "false", "f", "no", "n", "off":
+        return false, true
+    }
+    return false, false
+}
+
+// parseCellValue turns edited text into the value to bind for column c: the
+// literal NULL is SQL NULL (rejected for NOT NULL columns), numbers are parsed
+// for numeric columns, booleans become 0/1 and dates must be well-formed.
+func parseCellValue(c colInfo, input string) (any, error) {
+    s := strings.TrimSpace(input)
+    if strings.EqualFold(s, "NULL") {
+        if c.NotNull {
+            return nil, validationError{c.Name, "column is NOT NULL"}
+        }
+        return nil, nil
+    }
+    bad := func(format string, args ...any) error {
+        return validationError{c.Name, fmt.Sprintf(format, args...)}
+    }
+    switch columnKind(c.Type) {
+    case "integer":
+        if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+            return n, nil
+        }
+        if f, err := strconv.ParseFloat(s, 64); err == nil && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+            return int64(f), nil
+        }
+        return nil, bad("%q is not an integer", s)
+    case "real":
+        f, err := strconv.ParseFloat(s, 64)
+        if err != nil {
+            return nil, bad("%q is not a number", s)
+        }
+        return f, nil
+    case "numeric":
+        if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+            return n, nil
+        }
+        if f, err := strconv.ParseFloat(s, 64); err == nil {
+            return f, nil
+        }
+        return nil, bad("%q is not a number", s)
+    case "boolean":
+        b, ok := parseBool(s)
+        if !ok {
+            return nil, bad("%q is not a boolean (use 1/0, true/false, yes/no)", s)
+        }
+        if b {
+            return int64(1), nil
+        }
+        return int64(0), nil
+    case "date":
+        if !matchesLayout(s, dateLayouts) {
+            return nil, bad("%q is not a date (YYYY-MM-DD)", s)
+        }
+        return s, nil
+    case "datetime":
+        if !matchesLayout(s, datetimeLayouts) {
+            return nil, bad("%q is not a datetime (YYYY-MM-DD HH:MM:SS)", s)
+        }
+        return s, nil
+    case "time":
+        if !matchesLayout(s, timeLayouts) {
+            return nil, bad("%q is not a time (HH:MM[:SS])", s)
+        }
+        return s, nil
+    }
+    // text, JSON (checked separately with json_valid) and untyped columns
+    // keep the input exactly as typed
+    return input, nil
+}
+
+// editSeed is the text the cell editor starts with: the stored value read raw
+// (so dates keep their stored form), falling back to the preview text.
+func (m model) editSeed(shown string) string {
+    loc, ok := m.rowLocator(m.selRow)
+    if !ok {
+        return shown
+    }
+    v, err := readCell(m.conn(), m.previewTable, loc, m.previewColumns[m.selCol])
+    if err != nil {
+        return shown
+    }
+    if b, isBytes := v.([]byte); isBytes && !isMostlyPrintable(string(b)) {
+        return shown
+    }
+    return formatValue(v)
+}
+
+// toggleBoolCell flips the selected boolean cell (NULL becomes true) through
+// the normal commitCellEdit path.
+func (m *model) toggleBoolCell(shown string) error {
+    b, _ := parseBool(shown)
+    next := "1"
+    if b {
+        next = "0"
+    }
+    m.cellEditor.setValue(next)
+    return m.commitCellEdit()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestColumnKind(t *testing.T) {
+    tests := map[string]string{
+        "INTEGER":       "integer",
+        "bigint":        "integer",
+        "VARCHAR(20)":   "text",
+        "":              "blob",
+        "BLOB":          "blob",
+        "DOUBLE":        "real",
+        "DECIMAL(10,2)": "numeric",
+        "BOOLEAN":       "boolean",
+        "JSON":          "json",
+        "DATE":          "date",
+        "DATETIME":      "datetime",
+        "TIMESTAMP":     "datetime",
+        "TIME":          "time",
+    }
+    for in, want := range tests {
+        if got := columnKind(in); got != want {
+            t.Errorf("columnKind(%q) = %s, want %s", in, got, want)
+        }
+    }
+}
+
+func TestParseCellValue(t *testing.T) {
+    tests := []struct {
+        typ     string
+        notNull bool
+        in      string
+        want    any
+        wantErr bool
+    }{
+        {"INTEGER", false, " 42 ", int64(42), false},
+        {"INTEGER", false, "1e3", int64(1000), false},
+        {"INTEGER", false, "1.5", nil, true},
+        {"INTEGER", false, "abc", nil, true},
+        {"INTEGER", false, "null", nil, false},
+        {"INTEGER", true, "NULL", nil, true},
+        {"REAL", false, "2.5", 2.5, false},
+        {"REAL", false, "x", nil, true},
+        {"NUMERIC", false, "7", int64(7), false},
+        {"NUMERIC", false, "7.25", 7.25, false},
+        {"BOOLEAN", false, "yes", int64(1), false},
+        {"BOOLEAN", false, "off", int64(0), false},
+        {"BOOLEAN", false, "maybe", nil, true},
+        {"DATE", false, "2024-02-29", "2024-02-29", false},
+        {"DATE", false, "2023-02-29", nil, true},
+        {"DATETIME", false, "2024-01-02T03:04:05Z", "2024-01-02T03:04:05Z", false},
+        {"DATETIME", false, "yesterday", nil, true},
+        {"TIME", false, "09:30", "09:30", false},
+        {"TIME", false, "25:00", nil, true},
+        {"TEXT", false, "  kept as typed ", "  kept as typed ", false},
+        {"", false, "007", "007", false},
+    }
+    for _, tt := range tests {
+        got, err := parseCellValue(colInfo{Name: "c", Type: tt.typ, NotNull: tt.notNull}, tt.in)
+        if tt.wantErr {
+            if _, ok := err.(validationError); !ok {
+                t.Errorf("%s %q: got %v, %v, want a validation error", tt.typ, tt.in, got, err)
+            }
+            continue
+        }
+        if err != nil || !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("%s %q: got %#v, %v, want %#v", tt.typ, tt.in, got, err, tt.want)
+        }
+    }
+}
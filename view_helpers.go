@@ -1,6 +1,145 @@
 package main
 
-func computeColumnWidths(cols []string, rows [][]string, maxWidth int) []int {
+import (
+    "fmt"
+    "strings"
+)
+
+// gridSpec describes a table of cells to draw: the preview pane and query
+// results share this renderer.
+type gridSpec struct {
+    cols     []string
+    rows     [][]string
+    frozen   []int // column indexes pinned on the left
+    offset   int   // first scrollable column shown
+    width    int   // room for columns, excluding the 2-char row gutter
+    focused  bool  // draw the row cursor and selected header
+    selRow   int
+    selCol   int
+    editor   *textArea // when set, drawn with its cursor in place of the selected cell
+    sortCol  string // column shown with a sort arrow
+    sortDesc bool
+    changed  map[[2]int]bool // (row, col) cells to highlight as staged edits
+    maxRows  int             // rows that fit on screen; 0 draws them all
+}
+
+// colWidths returns the natural column widths of g, including room for the
+// sort arrow.
+func (g gridSpec) colWidths() []int {
+    widths := computeColumnWidths(g.cols, g.rows)
+    if g.sortCol != "" {
+        if i := findColIndex(g.cols, g.sortCol); i >= 0 && widths[i] < 20 {
+            widths[i]++
+        }
+    }
+    return widths
+}
+
+// rowWindow returns the rows of g that are drawn: at most maxRows, scrolled
+// so the selected row stays on screen.
+func (g gridSpec) rowWindow() (start, end int) {
+    if g.maxRows <= 0 || len(g.rows) <= g.maxRows {
+        return 0, len(g.rows)
+    }
+    if g.selRow >= g.maxRows {
+        start = min(g.selRow-g.maxRows+1, len(g.rows)-g.maxRows)
+    }
+    return start, start + g.maxRows
+}
+
+// renderGrid writes the header, separator and rows of g to b.
+func renderGrid(b *strings.Builder, g gridSpec) {
+    vis, colWidths := fitColumns(g.colWidths(), g.frozen, g.offset, g.width)
+    // header (align with row gutter)
+    b.WriteString("  ")
+    for k, i := range vis {
+        headerText := g.cols[i]
+        if g.sortCol != "" && strings.EqualFold(g.sortCol, headerText) {
+            if g.sortDesc { headerText += "▼" } else { headerText += "▲" }
+        }
+        // add selection marker before truncation
+        isSelectedHeader := g.focused && i == g.selCol
+        if isSelectedHeader {
+            headerText = "*" + headerText
+        }
+        headerText = truncateCell(headerText, colWidths[i])
+        if isSelectedHeader {
+            headerText = styleColSelect.Render(headerText)
+        }
+        b.WriteString(padRightANSI(headerText, colWidths[i]))
+        if k < len(vis)-1 {
+            b.WriteString(" ")
+        }
+    }
+    b.WriteString("\n")
+    // separator (align with row gutter)
+    b.WriteString("  ")
+    for k, i := range vis {
+        b.WriteString(strings.Repeat("-", colWidths[i]))
+        if k < len(vis)-1 {
+            b.WriteString(" ")
+        }
+    }
+    b.WriteString("\n")
+    // rows
+    start, end := g.rowWindow()
+    for ri := start; ri < end; ri++ {
+        row := g.rows[ri]
+        // row cursor when focused
+        if g.focused && ri == g.selRow { b.WriteString(styleCursor.Render("> ")) } else { b.WriteString("  ") }
+        for k, i := range vis {
+            cell := ""
+            if i < len(row) { cell = row[i] }
+            if g.editor != nil && g.focused && ri == g.selRow && i == g.selCol {
+                // the cursor line, scrolled horizontally to keep the cursor visible
+                cell = padRightANSI(strings.Join(g.editor.render(colWidths[i], 1, true), ""), colWidths[i])
+            } else {
+                cell = padRightANSI(truncateCell(cell, colWidths[i]), colWidths[i])
+            }
+            if g.changed[[2]int{ri, i}] {
+                cell = styleChanged.Render(cell)
+            }
+            b.WriteString(cell)
+            if k < len(vis)-1 {
+                b.WriteString(" ")
+            }
+        }
+        b.WriteString("\n")
+    }
+}
+
+// columnSpan describes which columns of g are on screen when it is scrolled,
+// e.g. "cols 4-9/40"; it is empty when every column fits.
+func columnSpan(g gridSpec) string {
+    widths := g.colWidths()
+    vis := visibleColumns(widths, g.frozen, g.offset, g.width)
+    if len(vis) == len(g.cols) {
+        return ""
+    }
+    scroll := scrollableColumns(len(widths), g.frozen)
+    first := g.offset + 1
+    last := g.offset + len(vis) - len(g.frozen)
+    span := fmt.Sprintf("cols %d-%d/%d", first, last, len(scroll))
+    if len(g.frozen) > 0 {
+        span += " +PK"
+    }
+    return span
+}
+
+// rowSpan describes which rows of g are on screen when they don't all fit,
+// e.g. "rows 21-40/200"; it is empty otherwise.
+func rowSpan(g gridSpec) string {
+    start, end := g.rowWindow()
+    if end-start == len(g.rows) {
+        return ""
+    }
+    return fmt.Sprintf("rows %d-%d/%d", start+1, end, len(g.rows))
+}
+
+// computeColumnWidths returns the natural width of each column: the header
+// length (kept between 3 and 20) widened by cell contents up to 40 chars.
+// Columns are no longer squeezed to fit; the preview scrolls horizontally.
+func computeColumnWidths(cols []string, rows [][]string) []int {
     n := len(cols)
     if n == 0 {
         return nil
@@ -19,6 +158,9 @@ func computeColumnWidths(cols []string, rows [][]string, maxWidth int) []int {
     // Consider data lengths for a more informed default, capped
     for _, row := range rows {
         for i, cell := range row {
+            if i >= n {
+                break
+            }
             l := len(cell)
             if l > 40 {
                 l = 40
@@ -28,30 +170,89 @@ func computeColumnWidths(cols []string, rows [][]string, maxWidth int) []int {
             }
         }
     }
-    // Add minimal spaces between columns
-    total := sum(widths) + (n-1)*1
-    if total <= maxWidth {
-        return widths
-    }
-    // Need to shrink proportionally, but keep a minimum of 3 chars per column
-    toReduce := total - maxWidth
-    for toReduce > 0 {
-        // find column with largest width > 3 and reduce
-        idx := -1
-        maxW := 0
-        for i, w := range widths {
-            if w > maxW && w > 3 {
-                maxW = w
-                idx = i
+    return widths
+}
+
+// scrollableColumns returns the column indexes that are not frozen, in order.
+func scrollableColumns(n int, frozen []int) []int {
+    isFrozen := make(map[int]bool, len(frozen))
+    for _, f := range frozen { isFrozen[f] = true }
+    out := make([]int, 0, n)
+    for i := 0; i < n; i++ {
+        if !isFrozen[i] { out = append(out, i) }
+    }
+    return out
+}
+
+// visibleColumns picks the columns that fit into maxWidth (one space between
+// columns). Frozen columns always come first; scrollable columns follow starting
+// at offset. At least one scrollable column is included even if it must be
+// truncated.
+func visibleColumns(widths []int, frozen []int, offset int, maxWidth int) []int {
+    vis, _ := fitColumns(widths, frozen, offset, maxWidth)
+    return vis
+}
+
+// fitColumns is visibleColumns that also returns the widths to draw them at:
+// a first scrollable column too wide for the space left is narrowed to it.
+func fitColumns(widths []int, frozen []int, offset int, maxWidth int) ([]int, []int) {
+    fit := append([]int(nil), widths...)
+    vis := make([]int, 0, len(widths))
+    used := 0
+    add := func(i int) bool {
+        w := widths[i]
+        if len(vis) > 0 { w++ }
+        if used+w > maxWidth && len(vis) > 0 {
+            return false
+        }
+        vis = append(vis, i)
+        used += w
+        if used > maxWidth { fit[i] = max(1, maxWidth) } // a lone column wider than the grid
+        return true
+    }
+    for _, f := range frozen {
+        if f < len(widths) && !add(f) { break }
+    }
+    scroll := scrollableColumns(len(widths), frozen)
+    if offset < 0 { offset = 0 }
+    for k := offset; k < len(scroll); k++ {
+        if !add(scroll[k]) {
+            if k == offset {
+                // always show the first scrollable column, truncated
+                vis = append(vis, scroll[k])
+                fit[scroll[k]] = max(1, maxWidth-used-1)
             }
+            break
         }
-        if idx == -1 {
+    }
+    return vis, fit
+}
+
+// scrollOffsetFor returns the scroll offset (index into the scrollable columns)
+// that keeps column sel on screen, moving as little as possible from offset.
+func scrollOffsetFor(widths []int, frozen []int, sel, offset, maxWidth int) int {
+    scroll := scrollableColumns(len(widths), frozen)
+    if offset >= len(scroll) { offset = max(0, len(scroll)-1) }
+    if offset < 0 { offset = 0 }
+    pos := -1
+    for k, i := range scroll {
+        if i == sel { pos = k; break }
+    }
+    if pos < 0 {
+        // frozen (or unknown) column: it is always visible
+        return offset
+    }
+    if pos < offset {
+        return pos
+    }
+    for offset < pos {
+        vis := visibleColumns(widths, frozen, offset, maxWidth)
+        if len(vis) > 0 && vis[len(vis)-1] >= sel {
             break
         }
-        widths[idx]--
-        toReduce--
+        offset++
     }
-    return widths
+    return offset
 }
 
 func sum(v []int) int { s := 0; for _, x := range v { s += x }; return s }